@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitHub Actions 集成
+//
+// 在 Action 中运行时（环境变量由 runner 设置），workflow command 输出到 stderr，不影响 stdout 中的报告:
+//   - `GITHUB_ACTIONS=true`  未找到的 package 输出 `::warning::`，ERROR 日志输出 `::error::`
+//   - `GITHUB_STEP_SUMMARY`  写入 Markdown 运行汇总（package、未找到、失败、API 请求、文件变化）
+//   - `GITHUB_OUTPUT`        设置 step 输出 changed、total、not_found
+
+// 运行中的失败（ERROR 日志）
+type runErrors struct {
+	mutex    sync.Mutex
+	messages []string
+}
+
+// 进程级失败记录，由 [errorHandler] 写入
+var defaultRunErrors = &runErrors{}
+
+// 记录一次失败，在 GitHub Actions 中同时输出 `::error::` 注释
+//
+// 参数:
+//   - [message] 失败信息
+func (e *runErrors) add(message string) {
+	e.mutex.Lock()
+	e.messages = append(e.messages, message)
+	e.mutex.Unlock()
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		fmt.Fprintln(os.Stderr, workflowCommand("error", "", message))
+	}
+}
+
+// 获取失败记录副本
+func (e *runErrors) snapshot() []string {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return append([]string{}, e.messages...)
+}
+
+// 将 ERROR 日志记录到 [defaultRunErrors] 的 handler
+type errorHandler struct {
+	slog.Handler
+}
+
+func (h errorHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= slog.LevelError {
+		message := record.Message
+		record.Attrs(func(attr slog.Attr) bool {
+			message += " " + attr.String()
+			return true
+		})
+		defaultRunErrors.add(message)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h errorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return errorHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h errorHandler) WithGroup(name string) slog.Handler {
+	return errorHandler{h.Handler.WithGroup(name)}
+}
+
+// 组装 workflow command，如 `::warning title=xxx::message`
+//
+// 参数:
+//   - [name]    命令 warning | error
+//   - [title]   注释标题（为空时省略）
+//   - [message] 注释内容
+func workflowCommand(name string, title string, message string) string {
+	escapeData := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	escapeProperty := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+	command := "::" + name
+	if title != "" {
+		command += " title=" + escapeProperty.Replace(title)
+	}
+	return command + "::" + escapeData.Replace(message)
+}
+
+// 结束一次运行：输出运行汇总，在 GitHub Actions 中输出注释、step 汇总与 step 输出
+//
+// 参数:
+//   - [report] 运行结果
+func finishRun(report runReport) {
+	logRunSummary(report)
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		for _, value := range report.packages {
+			if value.Code == 0 {
+				fmt.Fprintln(os.Stderr, workflowCommand("warning", "Package not found", value.Name+" can not be found on ohpm"))
+			}
+		}
+	}
+	if filename := os.Getenv("GITHUB_STEP_SUMMARY"); filename != "" {
+		// 文件内容不经过 [redactStdio]，需单独隐藏密钥
+		summary := defaultRedactor.redact(assembleStepSummary(report, defaultRunErrors.snapshot(), defaultHTTPMetrics.snapshot()))
+		if err := appendFile(filename, summary); err != nil {
+			slog.Warn("🎬⚠️ GITHUB_STEP_SUMMARY", "error", err)
+		}
+	}
+	if filename := os.Getenv("GITHUB_OUTPUT"); filename != "" {
+		if err := appendFile(filename, assembleStepOutputs(report)); err != nil {
+			slog.Warn("🎬⚠️ GITHUB_OUTPUT", "error", err)
+		}
+	}
+}
+
+// 组装 step 汇总（Markdown）
+//
+// 参数:
+//   - [report]   运行结果
+//   - [failures] 失败信息
+//   - [hosts]    HTTP 指标（各 host 的请求次数即 API 额度消耗）
+func assembleStepSummary(report runReport, failures []string, hosts map[string]httpHostMetrics) string {
+	notFound, githubSkipped := report.counts()
+	status := "✅"
+	if len(failures) > 0 {
+		status = "❌"
+	} else if notFound > 0 || githubSkipped > 0 {
+		status = "⚠️"
+	}
+
+	out := strings.Builder{}
+	out.WriteString("### " + status + " ohpm-dashboard " + report.command + "\n\n")
+	out.WriteString("| Packages | Not found | GitHub skipped | Failures | Duration |\n")
+	out.WriteString("|---------:|----------:|---------------:|---------:|---------:|\n")
+	out.WriteString("| " + strconv.Itoa(len(report.packages)) + " | " + strconv.Itoa(notFound) + " | " + strconv.Itoa(githubSkipped) + " | " + strconv.Itoa(len(failures)) + " | " + time.Since(report.start).Round(time.Millisecond).String() + " |\n\n")
+
+	if report.file != "" {
+		if report.changed {
+			out.WriteString("**" + report.file + "**: changed, +" + strconv.Itoa(report.added) + " / -" + strconv.Itoa(report.removed) + " lines\n\n")
+		} else {
+			out.WriteString("**" + report.file + "**: unchanged\n\n")
+		}
+	}
+
+	if notFound > 0 {
+		out.WriteString("<details><summary>Not found (" + strconv.Itoa(notFound) + ")</summary>\n\n")
+		for _, value := range report.packages {
+			if value.Code == 0 {
+				out.WriteString("- `" + value.Name + "`\n")
+			}
+		}
+		out.WriteString("\n</details>\n\n")
+	}
+
+	if len(failures) > 0 {
+		out.WriteString("<details><summary>Failures (" + strconv.Itoa(len(failures)) + ")</summary>\n\n")
+		for _, message := range failures {
+			out.WriteString("- " + strings.ReplaceAll(message, "\n", " ") + "\n")
+		}
+		out.WriteString("\n</details>\n\n")
+	}
+
+	if len(hosts) > 0 {
+		names := []string{}
+		for host := range hosts {
+			names = append(names, host)
+		}
+		sort.Strings(names)
+		out.WriteString("| API host | Requests (quota used) | Errors |\n")
+		out.WriteString("|----------|----------------------:|-------:|\n")
+		for _, host := range names {
+			out.WriteString("| " + host + " | " + strconv.Itoa(hosts[host].Requests) + " | " + strconv.Itoa(hosts[host].Errors) + " |\n")
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// 组装 step 输出（`name=value` 格式）
+//
+// 参数:
+//   - [report] 运行结果
+func assembleStepOutputs(report runReport) string {
+	notFound, _ := report.counts()
+	return "changed=" + strconv.FormatBool(report.changed) + "\n" +
+		"total=" + strconv.Itoa(len(report.packages)) + "\n" +
+		"not_found=" + strconv.Itoa(notFound) + "\n"
+}
+
+// 统计两个版本之间新增、删除的行数（按行多重集合比较，忽略行的移动）
+//
+// 参数:
+//   - [before] 修改前内容
+//   - [after]  修改后内容
+//
+// 返回值:
+//   - 新增行数
+//   - 删除行数
+func diffLines(before string, after string) (int, int) {
+	counts := map[string]int{}
+	for _, line := range strings.Split(before, "\n") {
+		counts[line]++
+	}
+	added := 0
+	for _, line := range strings.Split(after, "\n") {
+		if counts[line] > 0 {
+			counts[line]--
+		} else {
+			added++
+		}
+	}
+	removed := 0
+	for _, count := range counts {
+		removed += count
+	}
+	return added, removed
+}
+
+// 追加写入文件（GITHUB_STEP_SUMMARY / GITHUB_OUTPUT 可能已有其他 step 的内容）
+//
+// 参数:
+//   - [filename] 文件名
+//   - [content]  内容
+func appendFile(filename string, content string) error {
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(file, content); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
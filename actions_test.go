@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestWorkflowCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		title   string
+		message string
+		want    string
+	}{
+		{"plain", "error", "", "fetch failed", "::error::fetch failed"},
+		{"title", "warning", "Package not found", "@a/b", "::warning title=Package not found::@a/b"},
+		{"escaped", "warning", "a: b, c", "100%\nnext", "::warning title=a%3A b%2C c::100%25%0Anext"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := workflowCommand(tt.command, tt.title, tt.message); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	added, removed := diffLines("a\nb\nc\n", "a\nc\nd\ne\n")
+	if added != 2 || removed != 1 {
+		t.Errorf("got (+%d, -%d), want (+2, -1)", added, removed)
+	}
+	if added, removed := diffLines("a\nb\n", "b\na\n"); added != 0 || removed != 0 {
+		t.Errorf("moved lines: got (+%d, -%d)", added, removed)
+	}
+}
+
+func TestAssembleStepSummary(t *testing.T) {
+	report := runReport{command: "fetch", start: time.Now(), packages: []ohpm.PackageInfo{{Code: 1, Name: "@a/ok"}, {Code: 0, Name: "@a/missing"}}}
+	hosts := map[string]httpHostMetrics{"api.github.com": {Requests: 12, Errors: 1}, "ohpm.openharmony.cn": {Requests: 8}}
+	got := assembleStepSummary(report, []string{"📦⚠️ PackageInfo: boom"}, hosts)
+	for _, want := range []string{
+		"### ❌ ohpm-dashboard fetch",
+		"| 2 | 1 | 0 | 1 |",
+		"<details><summary>Not found (1)</summary>",
+		"- `@a/missing`",
+		"- 📦⚠️ PackageInfo: boom",
+		"| api.github.com | 12 | 1 |\n| ohpm.openharmony.cn | 8 | 0 |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "changed") {
+		t.Errorf("unexpected file stats for fetch:\n%s", got)
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	defaultRunErrors = &runErrors{}
+	defer func() { defaultRunErrors = &runErrors{} }()
+	t.Setenv("GITHUB_ACTIONS", "true")
+
+	filename := filepath.Join(t.TempDir(), "stderr")
+	file, _ := os.Create(filename)
+	stderr := os.Stderr
+	os.Stderr = file
+	defer func() { os.Stderr = stderr }()
+
+	logger, _ := newLogger(&bytes.Buffer{}, "text")
+	logger.Warn("not a failure")
+	logger.With("command", "update").Error("🌐❌ serve", "error", "boom")
+	file.Close()
+
+	if got := defaultRunErrors.snapshot(); len(got) != 1 || got[0] != "🌐❌ serve error=boom" {
+		t.Errorf("failures = %q", got)
+	}
+	data, _ := os.ReadFile(filename)
+	if got := string(data); got != "::error::🌐❌ serve error=boom\n" {
+		t.Errorf("annotation = %q", got)
+	}
+}
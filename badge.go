@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/AmosHuKe/ohpm-dashboard/dashboard"
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// 生成所有 package 的 shields.io endpoint 文件
+//
+// 文件路径：[dir]/{package 名称}/{指标}.json，如 "badges/@candies/extended_text/points.json"
+//
+// 参数:
+//   - [dir]             输出目录
+//   - [packageInfoList] 信息列表
+func writeShieldsEndpoints(dir string, packageInfoList []ohpm.PackageInfo) error {
+	for _, value := range packageInfoList {
+		// package 名称作为路径，拒绝 `..` 等越界路径
+		packagePath := filepath.FromSlash(value.Name)
+		if !filepath.IsLocal(packagePath) {
+			return fmt.Errorf("🏷️❌ writeShieldsEndpoints: invalid package name %q", value.Name)
+		}
+		packageDir := filepath.Join(dir, packagePath)
+		if err := os.MkdirAll(packageDir, 0755); err != nil {
+			return fmt.Errorf("🏷️❌ writeShieldsEndpoints: %w", err)
+		}
+		for _, metric := range dashboard.BadgeMetrics {
+			endpoint, _ := dashboard.AssembleShieldsEndpoint(value, metric)
+			data, err := json.Marshal(endpoint)
+			if err != nil {
+				return fmt.Errorf("🏷️❌ writeShieldsEndpoints: %w", err)
+			}
+			if err := os.WriteFile(filepath.Join(packageDir, metric+".json"), data, 0644); err != nil {
+				return fmt.Errorf("🏷️❌ writeShieldsEndpoints: Error writing a file: %w", err)
+			}
+		}
+	}
+	slog.Info("🏷️✅ writeShieldsEndpoints: Success", "dir", dir)
+	return nil
+}
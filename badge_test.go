@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AmosHuKe/ohpm-dashboard/dashboard"
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestWriteShieldsEndpoints(t *testing.T) {
+	dir := t.TempDir()
+	list := []ohpm.PackageInfo{{Code: 1, Name: "@a/b", Version: "1.0.0", Points: 50, MaxPoints: 50}}
+	if err := writeShieldsEndpoints(dir, list); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, metric := range dashboard.BadgeMetrics {
+		data, err := os.ReadFile(filepath.Join(dir, "@a", "b", metric+".json"))
+		if err != nil {
+			t.Fatalf("%s: %v", metric, err)
+		}
+		var got dashboard.ShieldsEndpoint
+		if err := json.Unmarshal(data, &got); err != nil || got.SchemaVersion != 1 {
+			t.Errorf("%s: got %+v, err %v", metric, got, err)
+		}
+	}
+
+	if err := writeShieldsEndpoints(dir, []ohpm.PackageInfo{{Name: "../evil"}}); err == nil {
+		t.Error("expected error for path traversal")
+	}
+}
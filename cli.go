@@ -0,0 +1,546 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/dashboard"
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// 进程退出码
+const (
+	exitOK       = 0 // 成功
+	exitFindings = 1 // check / lint 发现问题
+	exitError    = 2 // 参数错误或运行失败（与 flag 解析失败的退出码一致）
+)
+
+// 子命令说明
+const (
+	updateSummary = "Fetch packages and update the placeholders in a Markdown file (default command)."
+	fetchSummary  = "Fetch packages and write them to a data file for render."
+	renderSummary = "Render a data file as Markdown, HTML or JSON, without network access."
+	checkSummary  = "Fetch packages and report license issues and GitHub versions ahead of ohpm; exit 1 on findings."
+	lintSummary   = "Audit the ohpm metadata of packages; exit 1 on findings."
+	serveSummary  = "Serve live dashboards over HTTP."
+)
+
+// 子命令
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+// 支持的子命令（无子命令时为 update）
+var commands = []command{
+	{"update", updateSummary, runUpdate},
+	{"fetch", fetchSummary, runFetch},
+	{"render", renderSummary, runRender},
+	{"check", checkSummary, runCheck},
+	{"lint", lintSummary, runLint},
+	{"serve", serveSummary, runServe},
+}
+
+// 解析子命令并执行
+//
+// 参数:
+//   - [args] 命令行参数（不含程序名）
+//
+// 返回值:
+//   - 进程退出码
+func run(args []string) int {
+	// 兼容旧用法：`go run . -githubToken xxx ...` 等同于 update
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runUpdate(args)
+	}
+	if args[0] == "help" {
+		printCommandsUsage(os.Stdout)
+		return exitOK
+	}
+	for _, value := range commands {
+		if value.name == args[0] {
+			return value.run(args[1:])
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+	printCommandsUsage(os.Stderr)
+	return exitError
+}
+
+// 输出子命令列表
+func printCommandsUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: ohpm-dashboard <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, value := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", value.name, value.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run `ohpm-dashboard <command> -h` for the flags of a command.")
+}
+
+// 创建子命令参数集（`-h` 时输出子命令说明与参数），包含通用的日志参数
+//
+// 参数:
+//   - [name]    子命令名称
+//   - [summary] 子命令说明
+func newCommandFlagSet(name string, summary string) *flag.FlagSet {
+	// ContinueOnError：flag 不直接调用 os.Exit，main 总能执行 [redactStdio] 的 restore 输出缓冲内容
+	flagSet := flag.NewFlagSet(name, flag.ContinueOnError)
+	flagSet.Var(logLevelFlag{}, "log-level", "日志级别 debug | info | warn | error")
+	flagSet.Var(&logFormatFlag{}, "log-format", "日志格式 text | json")
+	flagSet.Usage = func() {
+		fmt.Fprintf(flagSet.Output(), "Usage: ohpm-dashboard %s [flags]\n\n%s\n\nFlags:\n", name, summary)
+		flagSet.PrintDefaults()
+	}
+	return flagSet
+}
+
+// 解析子命令参数（错误信息与参数说明由 flag 输出）
+//
+// 参数:
+//   - [flagSet] 参数集
+//   - [args]    命令行参数
+//
+// 返回值:
+//   - 进程退出码（`-h` 时为 [exitOK]，解析失败时为 [exitError]）
+//   - 是否解析成功
+func parseCommandFlags(flagSet *flag.FlagSet, args []string) (int, bool) {
+	if err := flagSet.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+		return exitError, false
+	}
+	return exitOK, true
+}
+
+// 注册抓取参数（客户端参数与仪表盘通用参数）
+//
+// 参数:
+//   - [flagSet] 参数集
+//   - [options] 解析结果
+//   - [config]  解析结果
+func registerFetchFlags(flagSet *flag.FlagSet, options *clientOptions, config *dashboard.Config) {
+	flagSet.StringVar(&options.githubToken, "githubToken", "", "Github Token with repo permissions，为空时依次读取 githubTokenFile、GITHUB_TOKEN、GH_TOKEN，均为空时匿名访问（额度耗尽后仅显示 Github 链接）")
+	flagSet.StringVar(&options.githubTokenFile, "githubTokenFile", "", "Github 令牌文件（避免令牌出现在进程列表中）")
+	flagSet.StringVar(&options.registryURL, "registryURL", ohpm.DefaultBaseURL, "ohpm registry API 地址 如: https://ohpm.example.com")
+	flagSet.StringVar(&options.registryWebURL, "registryWebURL", "", "ohpm registry 网页地址（package 链接），为空时同 registryURL")
+	flagSet.StringVar(&options.registryAuth, "registryAuth", "", "私有 registry 的 Authorization 请求头，为空时读取环境变量 OHPM_REGISTRY_AUTH 如: Bearer xxx")
+	flagSet.StringVar(&options.registryConfig, "registryConfig", "", "其他具名 registry 的 JSON 文件 如: registries.json")
+	flagSet.StringVar(&options.githubAPIURL, "githubAPIURL", ohpm.DefaultGithubAPIURL, "Github API 地址")
+	flagSet.StringVar(&options.githubWebURL, "githubWebURL", ohpm.DefaultGithubWebURL, "Github 网页地址")
+	flagSet.StringVar(&options.githubConfig, "githubConfig", "", "GitHub Enterprise Server 主机的 JSON 文件 如: github-hosts.json")
+	registerDashboardFlags(flagSet, config)
+}
+
+// update 子命令：抓取数据并更新 Markdown 文件（以及可选的徽章、feed、指标、通知）
+//
+// 返回值:
+//   - 进程退出码
+func runUpdate(args []string) int {
+	var filename, badgeDir, metricsFile, feedFile, stateFile, webhookList, downloadMilestones, saveSnapshot, fromSnapshot string
+	var check, commit bool
+	var commitOpts commitOptions
+	var options clientOptions
+	var config dashboard.Config
+	flagSet := newCommandFlagSet("update", updateSummary)
+	registerFetchFlags(flagSet, &options, &config)
+	flagSet.StringVar(&filename, "filename", "README.md", "文件名 如: README.md")
+	flagSet.StringVar(&badgeDir, "badgeDir", "", "shields.io endpoint 徽章输出目录 如: badges")
+	flagSet.StringVar(&metricsFile, "metricsFile", "", "Prometheus textfile collector 输出文件 如: ohpm.prom")
+	flagSet.StringVar(&feedFile, "feedFile", "", "Atom feed 输出文件 如: releases.atom")
+	flagSet.StringVar(&stateFile, "stateFile", "", "上一次运行的快照文件 如: .ohpm-dashboard.json")
+	flagSet.StringVar(&webhookList, "webhookList", "", "Webhook 列表，为空时读取环境变量 OHPM_WEBHOOKS 如: slack=https://hooks.slack.com/xxx,https://example.com/hook")
+	flagSet.StringVar(&downloadMilestones, "downloadMilestones", "1000,10000,100000,1000000", "下载量里程碑 如: 1000,10000")
+	flagSet.BoolVar(&check, "check", false, "所有文件更新之后，存在 check 问题时退出码为 1")
+	flagSet.StringVar(&saveSnapshot, "saveSnapshot", "", "将抓取结果保存为快照文件 如: snapshot.json")
+	flagSet.StringVar(&fromSnapshot, "fromSnapshot", "", "从快照文件读取数据，跳过抓取 如: snapshot.json")
+	flagSet.BoolVar(&commit, "commit", false, "提交更新的文件（无变化时跳过），见 commit.go")
+	flagSet.BoolVar(&commitOpts.push, "push", false, "提交后推送到 origin 的当前分支（需 -commit），被拒绝时 rebase 后重试")
+	flagSet.StringVar(&commitOpts.message, "commitMessage", "docs(ohpm-dashboard): ohpm-dashboard has updated readme", "提交信息")
+	flagSet.StringVar(&commitOpts.authorName, "commitAuthorName", "github-actions[bot]", "提交作者名称")
+	flagSet.StringVar(&commitOpts.authorEmail, "commitAuthorEmail", "41898282+github-actions[bot]@users.noreply.github.com", "提交作者邮箱")
+	if code, ok := parseCommandFlags(flagSet, args); !ok {
+		return code
+	}
+	report := runReport{command: "update", start: time.Now(), file: filename}
+	defer func() { finishRun(report) }()
+
+	webhookTargets, err := parseWebhookTargets(flagOrEnv(webhookList, "OHPM_WEBHOOKS"))
+	if err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	for _, target := range webhookTargets {
+		defaultRedactor.add(target.Url)
+	}
+	milestones, err := parseMilestones(downloadMilestones)
+	if err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+
+	ctx := context.Background()
+	client, err := newClient(options)
+	if err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+
+	snapshot, err := loadDashboard(ctx, client, config, fromSnapshot)
+	if err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	if saveSnapshot != "" {
+		if err := writeSnapshot(saveSnapshot, snapshot); err != nil {
+			slog.Error(err.Error())
+			return exitError
+		}
+	}
+	packageInfoList, publisherProfiles := snapshot.Packages, snapshot.Publishers
+	report.packages = packageInfoList
+	findingTotal := printCheckFindings(packageInfoList)
+
+	// 更新表格
+	before, _ := os.ReadFile(filename)
+	if err := updateMarkdownTable(filename, dashboard.AssembleMarkdownTable(packageInfoList, config.SortField)); err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	// 更新总数
+	if err := updateMarkdownPackageTotal(filename, len(packageInfoList)); err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	// 更新版本历史
+	if err := updateMarkdownBlock(filename, dashboard.BlockChangelog, dashboard.AssembleMarkdownChangelog(packageInfoList), "updateMarkdownChangelog"); err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	// 更新依赖关系图
+	if err := updateMarkdownBlock(filename, dashboard.BlockDependencyGraph, dashboard.AssembleMarkdownDependencyGraph(packageInfoList), "updateMarkdownDependencyGraph"); err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	// 更新 Publisher 汇总
+	if err := updateMarkdownBlock(filename, dashboard.BlockPublishers, dashboard.AssembleMarkdownPublisherSummary(publisherProfiles, packageInfoList), "updateMarkdownPublisherSummary"); err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	// 更新需要关注的 Package
+	if err := updateMarkdownBlock(filename, dashboard.BlockAttention, dashboard.AssembleMarkdownAttention(packageInfoList), "updateMarkdownAttention"); err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	after, _ := os.ReadFile(filename)
+	report.changed = !bytes.Equal(before, after)
+	report.added, report.removed = diffLines(string(before), string(after))
+	// 生成徽章
+	if badgeDir != "" {
+		if err := writeShieldsEndpoints(badgeDir, packageInfoList); err != nil {
+			slog.Error(err.Error())
+			return exitError
+		}
+	}
+	// 生成 Atom feed
+	if feedFile != "" {
+		if err := writeAtomFeed(feedFile, packageInfoList); err != nil {
+			slog.Error(err.Error())
+			return exitError
+		}
+	}
+	// 生成 Prometheus 指标
+	if metricsFile != "" {
+		if err := writeMetricsFile(metricsFile, assemblePrometheusMetrics(packageInfoList, defaultHTTPMetrics.snapshot())); err != nil {
+			slog.Error(err.Error())
+			return exitError
+		}
+	}
+	// 对比上一次快照并发送通知
+	if stateFile != "" {
+		previous, ok, err := readSnapshot(stateFile)
+		if err != nil {
+			slog.Error(err.Error())
+			return exitError
+		}
+		if ok {
+			events := detectNotifyEvents(previous.Packages, packageInfoList, milestones)
+			slog.Info("🔔 detectNotifyEvents", "events", len(events))
+			// 通知失败不影响仪表盘更新，且仍需写入快照，避免下次重复通知
+			if err := sendNotifications(ctx, client.HTTPClient, webhookTargets, events); err != nil {
+				slog.Warn(err.Error())
+			}
+		}
+		if err := writeSnapshot(stateFile, Snapshot{GeneratedAt: time.Now(), Packages: packageInfoList}); err != nil {
+			slog.Error(err.Error())
+			return exitError
+		}
+	}
+	// 提交（并推送）更新的文件
+	if commit {
+		paths := []string{filename}
+		for _, path := range []string{badgeDir, feedFile, metricsFile, stateFile, saveSnapshot} {
+			if path != "" {
+				paths = append(paths, path)
+			}
+		}
+		commitOpts.hosts = append(append([]ohpm.GithubHost{}, client.GithubHosts...), ohpm.GithubHost{WebURL: client.GithubWebURL, Token: client.GithubToken})
+		if _, err := commitFiles(ctx, paths, commitBody(report), commitOpts); err != nil {
+			slog.Error(err.Error())
+			return exitError
+		}
+	}
+	// 检查模式：所有文件更新之后再报告问题
+	if check && findingTotal > 0 {
+		return exitFindings
+	}
+	return exitOK
+}
+
+// fetch 子命令：抓取数据并写入数据文件（[Snapshot] 格式）
+//
+// 返回值:
+//   - 进程退出码
+func runFetch(args []string) int {
+	var output string
+	var options clientOptions
+	var config dashboard.Config
+	flagSet := newCommandFlagSet("fetch", fetchSummary)
+	registerFetchFlags(flagSet, &options, &config)
+	flagSet.StringVar(&output, "output", "ohpm-dashboard.json", "数据文件 如: data.json")
+	if code, ok := parseCommandFlags(flagSet, args); !ok {
+		return code
+	}
+
+	report := runReport{command: "fetch", start: time.Now()}
+	defer func() { finishRun(report) }()
+	client, err := newClient(options)
+	if err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	snapshot, err := loadDashboard(context.Background(), client, config, "")
+	if err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	report.packages = snapshot.Packages
+	if err := writeSnapshot(output, snapshot); err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	return exitOK
+}
+
+// 获取仪表盘数据：抓取 package 与 publisher 信息，或从快照文件读取（跳过全部网络请求）
+//
+// 参数:
+//   - [ctx]          上下文
+//   - [client]       ohpm 客户端
+//   - [config]       仪表盘配置
+//   - [fromSnapshot] 快照文件（为空时抓取）
+//
+// 返回值:
+//   - [Snapshot] 数据（package 已按 [config] 计算健康度、检查 license 并排序）
+func loadDashboard(ctx context.Context, client *ohpm.Client, config dashboard.Config, fromSnapshot string) (Snapshot, error) {
+	if fromSnapshot != "" {
+		snapshot, ok, err := readSnapshot(fromSnapshot)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		if !ok {
+			return Snapshot{}, fmt.Errorf("💾❌ readSnapshot: %s not found", fromSnapshot)
+		}
+		snapshot.Packages, err = dashboard.Prepare(snapshot.Packages, config)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		slog.Info("💾 loadDashboard: from snapshot", "packages", len(snapshot.Packages), "file", fromSnapshot, "generatedAt", snapshot.GeneratedAt)
+		return snapshot, nil
+	}
+
+	packageInfoList, err := dashboard.Fetch(ctx, client, config)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	publisherProfiles, err := client.PublisherProfiles(ctx, config.PublisherList)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return Snapshot{GeneratedAt: time.Now(), Packages: packageInfoList, Publishers: publisherProfiles}, nil
+}
+
+// render 子命令：将数据文件渲染为 Markdown 表格、HTML 页面或 JSON
+//
+// 返回值:
+//   - 进程退出码
+func runRender(args []string) int {
+	var input, output, format, sortField, sortMode string
+	flagSet := newCommandFlagSet("render", renderSummary)
+	flagSet.StringVar(&input, "input", "ohpm-dashboard.json", "fetch 生成的数据文件")
+	flagSet.StringVar(&output, "output", "", "输出文件（为空时输出到 stdout）")
+	flagSet.StringVar(&format, "format", "markdown", "markdown | html | json")
+	flagSet.StringVar(&sortField, "sortField", "name", "name | publishTime | ohpmLikes | ohpmDownloads | ohpmDependents | githubStars | healthScore")
+	flagSet.StringVar(&sortMode, "sortMode", "asc", "asc | desc")
+	if code, ok := parseCommandFlags(flagSet, args); !ok {
+		return code
+	}
+
+	snapshot, ok, err := readSnapshot(input)
+	if err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	if !ok {
+		slog.Error("🎨❌ render: data file not found, run `ohpm-dashboard fetch` first", "file", input)
+		return exitError
+	}
+	data, err := renderPackageInfo(snapshot, format, sortField, sortMode)
+	if err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	if output == "" {
+		os.Stdout.Write(data)
+		return exitOK
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		slog.Error("🎨❌ render: Error writing a file", "error", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// 渲染数据文件内容
+//
+// 参数:
+//   - [snapshot]  数据文件内容
+//   - [format]    输出格式 可选：markdown | html | json
+//   - [sortField] 排序字段
+//   - [sortMode]  排序方式
+//
+// 返回值:
+//   - 渲染结果
+func renderPackageInfo(snapshot Snapshot, format string, sortField string, sortMode string) ([]byte, error) {
+	packageInfoList := snapshot.Packages
+	dashboard.SortPackageInfo(packageInfoList, sortField, sortMode)
+	switch format {
+	case "markdown":
+		return []byte(dashboard.AssembleMarkdownTable(packageInfoList, sortField)), nil
+	case "html":
+		return []byte(dashboard.AssembleHTMLPage("render", dashboard.AssembleMarkdownTable(packageInfoList, sortField), snapshot.GeneratedAt)), nil
+	case "json":
+		data, err := json.MarshalIndent(packageInfoList, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("🎨❌ render: %w", err)
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("🎨❌ render: unknown format %q", format)
+	}
+}
+
+// check 子命令：抓取数据并检查问题（不写入任何文件）
+//
+// 返回值:
+//   - 进程退出码
+func runCheck(args []string) int {
+	var options clientOptions
+	var config dashboard.Config
+	flagSet := newCommandFlagSet("check", checkSummary)
+	registerFetchFlags(flagSet, &options, &config)
+	if code, ok := parseCommandFlags(flagSet, args); !ok {
+		return code
+	}
+
+	report := runReport{command: "check", start: time.Now()}
+	defer func() { finishRun(report) }()
+	client, err := newClient(options)
+	if err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	packageInfoList, err := dashboard.Fetch(context.Background(), client, config)
+	if err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	report.packages = packageInfoList
+	if printCheckFindings(packageInfoList) > 0 {
+		return exitFindings
+	}
+	return exitOK
+}
+
+// 输出 check 问题汇总（license 问题与 Github 领先 ohpm 的版本）
+//
+// 参数:
+//   - [packageInfoList] 信息列表
+//
+// 返回值:
+//   - 问题数量
+func printCheckFindings(packageInfoList []ohpm.PackageInfo) int {
+	return printVersionMismatches(packageInfoList) + printLicenseIssues(packageInfoList)
+}
+
+// 输出 Github 版本领先于 ohpm 版本的 package 汇总
+//
+// 参数:
+//   - [packageInfoList] 信息列表
+//
+// 返回值:
+//   - 领先的 package 数量
+func printVersionMismatches(packageInfoList []ohpm.PackageInfo) int {
+	mismatches := []ohpm.PackageInfo{}
+	for _, value := range packageInfoList {
+		if ohpm.IsGithubVersionAhead(value) {
+			mismatches = append(mismatches, value)
+		}
+	}
+	if len(mismatches) == 0 {
+		fmt.Println("🔖✅ Version: GitHub and ohpm are in sync")
+		return 0
+	}
+	fmt.Printf("🔖⚠️ Version: %d package(s) ahead on GitHub, forgot `ohpm publish`?\n", len(mismatches))
+	for _, value := range mismatches {
+		fmt.Printf("🔖   %s: ohpm v%s < GitHub %s (%s/%s)\n", value.Name, value.Version, value.GithubLatestVersion, value.GithubUser, value.GithubRepo)
+	}
+	return len(mismatches)
+}
+
+// 输出 license 问题汇总
+//
+// 参数:
+//   - [packageInfoList] 信息列表（需先 [dashboard.ApplyLicenseChecks]）
+//
+// 返回值:
+//   - 问题数量
+func printLicenseIssues(packageInfoList []ohpm.PackageInfo) int {
+	lines := []string{}
+	for _, value := range packageInfoList {
+		for _, issue := range value.LicenseIssues {
+			lines = append(lines, "📜   "+value.Name+": "+issue.Message)
+		}
+	}
+	if len(lines) == 0 {
+		fmt.Println("📜✅ License: no issues")
+		return 0
+	}
+	fmt.Printf("📜⚠️ License: %d issue(s)\n", len(lines))
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	return len(lines)
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/dashboard"
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestRun(t *testing.T) {
+	if code := run([]string{"help"}); code != exitOK {
+		t.Errorf("help exit code = %d, want %d", code, exitOK)
+	}
+	if code := run([]string{"unknown"}); code != exitError {
+		t.Errorf("unknown command exit code = %d, want %d", code, exitError)
+	}
+}
+
+// 构建后的程序输出 `-h` 与参数错误（stdout / stderr 经过 redactStdio 管道，flag 不能直接退出）
+func TestCommandFlagsBinary(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+	binary := filepath.Join(t.TempDir(), "ohpm-dashboard")
+	if output, err := exec.Command("go", "build", "-o", binary, ".").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, output)
+	}
+
+	for _, value := range commands {
+		output, err := exec.Command(binary, value.name, "-h").CombinedOutput()
+		if err != nil {
+			t.Errorf("%s -h: %v", value.name, err)
+		}
+		if !strings.Contains(string(output), "Usage: ohpm-dashboard "+value.name) || !strings.Contains(string(output), "-log-level") {
+			t.Errorf("%s -h output:\n%s", value.name, output)
+		}
+	}
+
+	output, err := exec.Command(binary, "update", "-bogus").CombinedOutput()
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != exitError {
+		t.Errorf("update -bogus: %v", err)
+	}
+	if !strings.Contains(string(output), "flag provided but not defined: -bogus") {
+		t.Errorf("update -bogus output:\n%s", output)
+	}
+}
+
+func TestRunRender(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "data.json")
+	generatedAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	list := []ohpm.PackageInfo{
+		{Code: 1, Name: "@a/b", Version: "1.0.0", Downloads: 10},
+		{Code: 1, Name: "@a/c", Version: "2.0.0", Downloads: 20},
+	}
+	if err := writeSnapshot(input, Snapshot{GeneratedAt: generatedAt, Packages: list}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("markdown", func(t *testing.T) {
+		output := filepath.Join(dir, "table.md")
+		if code := run([]string{"render", "-input", input, "-output", output, "-sortField", "ohpmDownloads", "-sortMode", "desc"}); code != exitOK {
+			t.Fatalf("exit code = %d", code)
+		}
+		data, _ := os.ReadFile(output)
+		markdown := string(data)
+		if !strings.HasPrefix(markdown, "<sub>Sort by ohpmDownloads | Total 2</sub>") {
+			t.Errorf("unexpected caption:\n%s", markdown)
+		}
+		if strings.Index(markdown, "[@a/c]") > strings.Index(markdown, "[@a/b]") {
+			t.Error("expected @a/c (more downloads) first")
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		output := filepath.Join(dir, "data.out.json")
+		if code := run([]string{"render", "-input", input, "-output", output, "-format", "json"}); code != exitOK {
+			t.Fatalf("exit code = %d", code)
+		}
+		data, _ := os.ReadFile(output)
+		var got []ohpm.PackageInfo
+		if err := json.Unmarshal(data, &got); err != nil || len(got) != 2 || got[0].Name != "@a/b" {
+			t.Errorf("got %+v, err %v", got, err)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		data, err := renderPackageInfo(Snapshot{GeneratedAt: generatedAt, Packages: list}, "html", "name", "asc")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(string(data), "<!DOCTYPE html>") || !strings.Contains(string(data), "<table>") {
+			t.Errorf("unexpected html:\n%s", data)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if code := run([]string{"render", "-input", filepath.Join(dir, "missing.json")}); code != exitError {
+			t.Errorf("missing input exit code = %d, want %d", code, exitError)
+		}
+		if code := run([]string{"render", "-input", input, "-format", "pdf"}); code != exitError {
+			t.Errorf("unknown format exit code = %d, want %d", code, exitError)
+		}
+	})
+}
+
+func TestRunUpdateFromSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "README.md")
+	placeholders := []string{"OHPMDashboard", "OHPMDashboard-total", "OHPMDashboard-changelog", "OHPMDashboard-dependencyGraph", "OHPMDashboard-publishers", "OHPMDashboard-attention"}
+	content := "# test\n"
+	for _, name := range placeholders {
+		content += "<!-- md:" + name + " begin --><!-- md:" + name + " end -->\n"
+	}
+	os.WriteFile(filename, []byte(content), 0644)
+	saved := filepath.Join(dir, "saved.json")
+	stepSummary := filepath.Join(dir, "step_summary.md")
+	stepOutput := filepath.Join(dir, "step_output")
+	t.Setenv("GITHUB_ACTIONS", "")
+	t.Setenv("GITHUB_STEP_SUMMARY", stepSummary)
+	t.Setenv("GITHUB_OUTPUT", stepOutput)
+
+	code := run([]string{"update", "-fromSnapshot", "testdata/snapshot.json", "-filename", filename, "-saveSnapshot", saved, "-licenseAllowlist", "MIT", "-check"})
+	if code != exitFindings {
+		t.Fatalf("exit code = %d, want %d (missing license, GitHub ahead)", code, exitFindings)
+	}
+	data, _ := os.ReadFile(stepOutput)
+	if got := string(data); got != "changed=true\ntotal=3\nnot_found=1\n" {
+		t.Errorf("step output = %q", got)
+	}
+	data, _ = os.ReadFile(stepSummary)
+	for _, want := range []string{"ohpm-dashboard update", "**" + filename + "**: changed", "- `@candies/missing`"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("missing %q in step summary:\n%s", want, data)
+		}
+	}
+
+	data, _ = os.ReadFile(filename)
+	markdown := string(data)
+	for _, want := range []string{
+		"[@candies/extended_text](https://ohpm.openharmony.cn/#/cn/detail/@candies%2Fextended_text)",
+		"@candies/missing ⁉️",
+		"<!-- md:OHPMDashboard-total begin -->3<!-- md:OHPMDashboard-total end -->",
+		"⚠️ Changelog 40/50 · changelog is not up to date",
+		"🔖 v1.2.0",
+		"<strong>License:</strong> - ⚠️ <em>no license on ohpm</em>",
+		"graph LR",
+		"HarmonyCandies",
+		"[@candies/missing](https://ohpm.openharmony.cn/#/cn/detail/@candies%2Fmissing) <sub><strong>0</strong> · not found</sub>",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("missing %q in:\n%s", want, markdown)
+		}
+	}
+
+	snapshot, ok, err := readSnapshot(saved)
+	if !ok || err != nil {
+		t.Fatalf("saved snapshot: ok = %v, err = %v", ok, err)
+	}
+	if snapshot.Version != snapshotVersion || len(snapshot.Packages) != 3 || len(snapshot.Publishers) != 1 {
+		t.Errorf("saved snapshot = %+v", snapshot)
+	}
+
+	if code := run([]string{"update", "-fromSnapshot", "testdata/snapshot.json", "-filename", filename}); code != exitOK {
+		t.Errorf("without -check exit code = %d, want %d", code, exitOK)
+	}
+	data, _ = os.ReadFile(stepOutput)
+	if !strings.HasSuffix(string(data), "changed=false\ntotal=3\nnot_found=1\n") {
+		t.Errorf("step output of unchanged run = %q", data)
+	}
+	if code := run([]string{"update", "-fromSnapshot", filepath.Join(dir, "missing.json"), "-filename", filename}); code != exitError {
+		t.Errorf("missing snapshot exit code = %d, want %d", code, exitError)
+	}
+}
+
+func TestReadSnapshotVersion(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "future.json")
+	os.WriteFile(filename, []byte(`{"version":99,"packages":[]}`), 0644)
+	if _, _, err := readSnapshot(filename); err == nil {
+		t.Error("expected error for newer snapshot version")
+	}
+}
+
+func TestPrintCheckFindings(t *testing.T) {
+	list := []ohpm.PackageInfo{
+		{Code: 1, Name: "ok", Version: "1.0.0", GithubLatestVersion: "v1.0.0"},
+		{Code: 1, Name: "ahead", Version: "1.0.0", GithubLatestVersion: "v1.1.0", LicenseIssues: []ohpm.LicenseIssue{{Kind: dashboard.LicenseIssueNotAllowed, Message: "GPL-3.0 not allowed"}}},
+		{Code: 1, Name: "none", LicenseIssues: []ohpm.LicenseIssue{{Kind: dashboard.LicenseIssueMissing, Message: "no license on ohpm"}}},
+	}
+	if total := printVersionMismatches(list); total != 1 {
+		t.Errorf("version mismatches = %d, want 1", total)
+	}
+	if total := printLicenseIssues(list); total != 2 {
+		t.Errorf("license issues = %d, want 2", total)
+	}
+	if total := printCheckFindings(list); total != 3 {
+		t.Errorf("check findings = %d, want 3", total)
+	}
+}
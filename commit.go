@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// push 被拒绝（远端有新提交）时 rebase 后重试的次数
+const pushRetries = 3
+
+// 提交参数
+type commitOptions struct {
+	message     string            // 提交信息（标题），正文为变化汇总
+	authorName  string            // 作者名称（同时作为提交者）
+	authorEmail string            // 作者邮箱
+	push        bool              // 提交后推送到 origin 的当前分支
+	hosts       []ohpm.GithubHost // 推送使用的令牌，按 origin 的主机匹配（仅 http(s) 远端），未匹配时使用 git 已有的凭据
+}
+
+// 提交并推送更新的文件
+//
+// 只暂存、提交 [paths]（工作区中其他改动不受影响），没有变化时跳过提交。
+// 推送被拒绝（non-fast-forward）时 `git pull --rebase --autostash` 后重试，最多 [pushRetries] 次。
+//
+// 参数:
+//   - [ctx]     上下文
+//   - [paths]   更新的文件或目录（以第一个所在的 git 仓库为准，仓库之外的跳过）
+//   - [body]    提交信息正文（变化汇总）
+//   - [options] 提交参数
+//
+// 返回值:
+//   - 是否创建了提交
+func commitFiles(ctx context.Context, paths []string, body string, options commitOptions) (bool, error) {
+	printErrTitle := "📝❌ commitFiles: "
+	if len(paths) == 0 {
+		return false, nil
+	}
+	dir := filepath.Dir(paths[0])
+	if info, err := os.Stat(paths[0]); err == nil && info.IsDir() {
+		dir = paths[0]
+	}
+	identity := []string{"-c", "user.name=" + options.authorName, "-c", "user.email=" + options.authorEmail}
+	toplevel, err := runGit(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return false, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+
+	// 只暂存更新的文件（-A 包含目录中删除的文件），仓库之外的文件（如 textfile collector 目录中的指标文件）跳过
+	pathspec := []string{}
+	for _, path := range paths {
+		absolute, err := filepath.Abs(path)
+		if err != nil {
+			return false, fmt.Errorf("%s%w", printErrTitle, err)
+		}
+		if resolved, err := filepath.EvalSymlinks(absolute); err == nil {
+			absolute = resolved
+		}
+		if relative, err := filepath.Rel(toplevel, absolute); err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			slog.Info("📝 commitFiles: skip file outside the repository", "file", path)
+			continue
+		}
+		pathspec = append(pathspec, absolute)
+	}
+	if len(pathspec) == 0 {
+		return false, nil
+	}
+	if _, err := runGit(ctx, dir, append([]string{"add", "-A", "--"}, pathspec...)...); err != nil {
+		return false, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	// diff --quiet：退出码 0 无变化，1 有变化，其他为错误
+	_, err = runGit(ctx, dir, append([]string{"diff", "--cached", "--quiet", "--"}, pathspec...)...)
+	if err == nil {
+		slog.Info("📝 commitFiles: nothing to commit")
+		return false, nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		return false, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	message := options.message
+	if body != "" {
+		message += "\n\n" + body
+	}
+	args := append(identity, "commit", "--quiet", "-m", message, "--")
+	if _, err := runGit(ctx, dir, append(args, pathspec...)...); err != nil {
+		return false, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	slog.Info("📝✅ commitFiles: Success", "files", len(pathspec))
+
+	if !options.push {
+		return true, nil
+	}
+	if err := pushWithRebase(ctx, dir, identity, options.hosts); err != nil {
+		return true, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	return true, nil
+}
+
+// 推送当前分支到 origin，被拒绝时 rebase 后重试
+//
+// 参数:
+//   - [ctx]      上下文
+//   - [dir]      仓库目录
+//   - [identity] 提交者配置（rebase 会重新创建提交）
+//   - [hosts]    Github 主机令牌
+func pushWithRebase(ctx context.Context, dir string, identity []string, hosts []ohpm.GithubHost) error {
+	branch, err := runGit(ctx, dir, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return errors.New("detached HEAD, check out a branch to push")
+	}
+	remote, err := runGit(ctx, dir, "remote", "get-url", "origin")
+	if err != nil {
+		return err
+	}
+	env := gitAuthEnv(remote, hosts)
+
+	for attempt := 1; ; attempt++ {
+		output, err := runGitEnv(ctx, dir, env, "push", "origin", "HEAD:refs/heads/"+branch)
+		if err == nil {
+			slog.Info("📝✅ pushWithRebase: Success", "branch", branch, "attempt", attempt)
+			return nil
+		}
+		rejected := strings.Contains(output, "[rejected]") || strings.Contains(output, "non-fast-forward") || strings.Contains(output, "fetch first")
+		if !rejected || attempt > pushRetries {
+			return err
+		}
+		slog.Warn("📝⚠️ pushWithRebase: rejected, rebasing onto origin", "branch", branch, "attempt", attempt)
+		args := append(append([]string{}, identity...), "pull", "--rebase", "--autostash", "--quiet", "origin", branch)
+		if _, err := runGitEnv(ctx, dir, env, args...); err != nil {
+			runGit(ctx, dir, "rebase", "--abort")
+			return err
+		}
+	}
+}
+
+// 推送使用的令牌：通过环境变量（`GIT_CONFIG_COUNT` 等）传递 extraheader 配置，
+// 不出现在命令行参数（进程列表）与仓库配置中，且仅发送给 origin 所在的主机
+//
+// 参数:
+//   - [remote] origin 地址
+//   - [hosts]  Github 主机令牌
+//
+// 返回值:
+//   - 追加的环境变量（未匹配到令牌时为空）
+func gitAuthEnv(remote string, hosts []ohpm.GithubHost) []string {
+	remoteURL, err := url.Parse(remote)
+	if err != nil || (remoteURL.Scheme != "https" && remoteURL.Scheme != "http") {
+		return nil
+	}
+	for _, host := range hosts {
+		webURL, err := url.Parse(strings.TrimSuffix(host.WebURL, "/"))
+		if err != nil || host.Token == "" || webURL.Host != remoteURL.Host {
+			continue
+		}
+		header := "AUTHORIZATION: basic " + base64.StdEncoding.EncodeToString([]byte("x-access-token:"+host.Token))
+		defaultRedactor.add(header)
+		return []string{
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http." + remoteURL.Scheme + "://" + remoteURL.Host + "/.extraheader",
+			"GIT_CONFIG_VALUE_0=" + header,
+		}
+	}
+	return nil
+}
+
+// 提交信息正文：package 数量与 Markdown 文件的变化
+//
+// 参数:
+//   - [report] 运行结果
+func commitBody(report runReport) string {
+	notFound, _ := report.counts()
+	body := fmt.Sprintf("Packages: %d, not found: %d", len(report.packages), notFound)
+	if report.file != "" && report.changed {
+		body += fmt.Sprintf("\n%s: +%d / -%d lines", filepath.Base(report.file), report.added, report.removed)
+	}
+	return body
+}
+
+// 执行 git 命令
+//
+// 参数:
+//   - [ctx]  上下文
+//   - [dir]  工作目录
+//   - [args] 参数
+//
+// 返回值:
+//   - 输出（stdout 与 stderr，已去除首尾空白）
+//   - 错误（退出码非 0 时包含输出）
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	return runGitEnv(ctx, dir, nil, args...)
+}
+
+// 执行 git 命令，追加环境变量 [env]，见 [runGit]
+func runGitEnv(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...) // 缺少凭据时直接失败，不等待输入
+	output := bytes.Buffer{}
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := cmd.Run()
+	text := strings.TrimSpace(output.String())
+	if err != nil {
+		// 错误中只包含子命令名称（跳过 `-c key=value`）
+		subcommand := ""
+		for i := 0; i < len(args); i++ {
+			if args[i] == "-c" {
+				i++
+				continue
+			}
+			subcommand = args[i]
+			break
+		}
+		return text, fmt.Errorf("git %s: %w: %s", subcommand, err, text)
+	}
+	return text, nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// 创建 bare 仓库（远端）与其克隆，返回克隆目录
+func newTestClone(t *testing.T, remote string) string {
+	t.Helper()
+	dir := t.TempDir()
+	mustGit(t, "", "clone", "--quiet", remote, dir)
+	return dir
+}
+
+func mustGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	identity := []string{"-c", "user.name=test", "-c", "user.email=test@example.com"}
+	output, err := runGit(context.Background(), dir, append(identity, args...)...)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return output
+}
+
+func TestCommitFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	ctx := context.Background()
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	mustGit(t, "", "init", "--quiet", "--bare", "--initial-branch=main", remote)
+	seed := newTestClone(t, remote)
+	mustGit(t, seed, "checkout", "--quiet", "-b", "main")
+	os.WriteFile(filepath.Join(seed, "README.md"), []byte("# test\n"), 0644)
+	os.WriteFile(filepath.Join(seed, "other.md"), []byte("other\n"), 0644)
+	mustGit(t, seed, "add", "-A")
+	mustGit(t, seed, "commit", "--quiet", "-m", "init")
+	mustGit(t, seed, "push", "--quiet", "origin", "main")
+
+	options := commitOptions{message: "docs: update", authorName: "bot", authorEmail: "bot@example.com", push: true}
+
+	t.Run("commits only updated files and pushes", func(t *testing.T) {
+		dir := newTestClone(t, remote)
+		readme := filepath.Join(dir, "README.md")
+		os.WriteFile(readme, []byte("# test\nupdated\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "other.md"), []byte("unrelated\n"), 0644)
+		os.MkdirAll(filepath.Join(dir, "badges"), 0755)
+		os.WriteFile(filepath.Join(dir, "badges", "a.json"), []byte("{}"), 0644)
+
+		// 仓库之外的文件（如 textfile collector 目录）跳过
+		metricsFile := filepath.Join(t.TempDir(), "ohpm.prom")
+		os.WriteFile(metricsFile, []byte("ohpm 1\n"), 0644)
+
+		committed, err := commitFiles(ctx, []string{readme, filepath.Join(dir, "badges"), metricsFile}, "Packages: 1, not found: 0", options)
+		if err != nil || !committed {
+			t.Fatalf("got %v, %v", committed, err)
+		}
+		if got := mustGit(t, dir, "log", "-1", "--format=%an <%ae>%n%B", "origin/main"); got != "bot <bot@example.com>\ndocs: update\n\nPackages: 1, not found: 0" {
+			t.Errorf("remote head:\n%s", got)
+		}
+		if got := mustGit(t, dir, "show", "--name-only", "--format=", "HEAD"); got != "README.md\nbadges/a.json" {
+			t.Errorf("committed files:\n%s", got)
+		}
+		if got := mustGit(t, dir, "status", "--porcelain"); got != "M other.md" {
+			t.Errorf("status:\n%s", got)
+		}
+	})
+
+	t.Run("skips empty commit", func(t *testing.T) {
+		dir := newTestClone(t, remote)
+		head := mustGit(t, dir, "rev-parse", "HEAD")
+		committed, err := commitFiles(ctx, []string{filepath.Join(dir, "README.md")}, "", options)
+		if err != nil || committed {
+			t.Fatalf("got %v, %v", committed, err)
+		}
+		if got := mustGit(t, dir, "rev-parse", "HEAD"); got != head {
+			t.Errorf("HEAD moved: %s -> %s", head, got)
+		}
+	})
+
+	t.Run("rebases and retries when rejected", func(t *testing.T) {
+		dir := newTestClone(t, remote)
+		// 另一个克隆先推送
+		other := newTestClone(t, remote)
+		os.WriteFile(filepath.Join(other, "other.md"), []byte("pushed first\n"), 0644)
+		mustGit(t, other, "commit", "--quiet", "-am", "other")
+		mustGit(t, other, "push", "--quiet", "origin", "main")
+
+		readme := filepath.Join(dir, "README.md")
+		os.WriteFile(readme, []byte("# test\nrebased\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "untracked.md"), []byte("untracked\n"), 0644)
+		committed, err := commitFiles(ctx, []string{readme}, "", options)
+		if err != nil || !committed {
+			t.Fatalf("got %v, %v", committed, err)
+		}
+		got := mustGit(t, dir, "log", "--format=%s", "origin/main")
+		if !strings.HasPrefix(got, "docs: update\nother\n") {
+			t.Errorf("remote log:\n%s", got)
+		}
+		if got := mustGit(t, dir, "status", "--porcelain"); got != "?? untracked.md" {
+			t.Errorf("status:\n%s", got)
+		}
+	})
+
+	t.Run("fails on detached HEAD", func(t *testing.T) {
+		dir := newTestClone(t, remote)
+		mustGit(t, dir, "checkout", "--quiet", "--detach")
+		readme := filepath.Join(dir, "README.md")
+		os.WriteFile(readme, []byte("# test\ndetached\n"), 0644)
+		if _, err := commitFiles(ctx, []string{readme}, "", options); err == nil || !strings.Contains(err.Error(), "detached HEAD") {
+			t.Errorf("got %v", err)
+		}
+	})
+}
+
+func TestCommitBody(t *testing.T) {
+	report := runReport{file: "docs/README.md", changed: true, added: 3, removed: 2}
+	if got := commitBody(report); got != "Packages: 0, not found: 0\nREADME.md: +3 / -2 lines" {
+		t.Errorf("got %q", got)
+	}
+	report.changed = false
+	if got := commitBody(report); got != "Packages: 0, not found: 0" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestGitAuthEnv(t *testing.T) {
+	resetDefaultRedactor(t)
+	hosts := []ohpm.GithubHost{
+		{WebURL: "https://github.example.com/", Token: "enterprise"},
+		{WebURL: "https://github.com", Token: "public"},
+	}
+	header := "AUTHORIZATION: basic " + base64.StdEncoding.EncodeToString([]byte("x-access-token:enterprise"))
+
+	env := gitAuthEnv("https://github.example.com/team/app.git", hosts)
+	want := []string{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.https://github.example.com/.extraheader", "GIT_CONFIG_VALUE_0=" + header}
+	if !slices.Equal(env, want) {
+		t.Errorf("got %q, want %q", env, want)
+	}
+	if got := defaultRedactor.redact(header); got != redactedText {
+		t.Errorf("header not redacted: %q", got)
+	}
+	for _, remote := range []string{"https://gitlab.com/team/app.git", "git@github.com:team/app.git", "/tmp/remote.git"} {
+		if env := gitAuthEnv(remote, hosts); env != nil {
+			t.Errorf("%s: got %q", remote, env)
+		}
+	}
+	if env := gitAuthEnv("https://github.com/team/app.git", []ohpm.GithubHost{{WebURL: "https://github.com"}}); env != nil {
+		t.Errorf("empty token: got %q", env)
+	}
+
+	// git 从环境变量读取配置（不经过命令行参数），且仅对该主机生效
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	got, err := runGitEnv(context.Background(), t.TempDir(), env, "config", "--get-urlmatch", "http.extraheader", "https://github.example.com/team/app.git")
+	if err != nil || got != header {
+		t.Errorf("got %q, %v", got, err)
+	}
+	if got, err := runGitEnv(context.Background(), t.TempDir(), env, "config", "--get-urlmatch", "http.extraheader", "https://github.com/team/app.git"); err == nil {
+		t.Errorf("header sent to another host: %q", got)
+	}
+}
@@ -0,0 +1,79 @@
+package dashboard
+
+import (
+	"strconv"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// shields.io endpoint 徽章（https://shields.io/badges/endpoint-badge）
+//
+// 为每个 package 的指标生成 endpoint JSON，可直接用于 README：
+//
+//	![points](https://img.shields.io/endpoint?url=https://example.com/badges/points/@candies/extended_text)
+type ShieldsEndpoint struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// 支持的徽章指标
+var BadgeMetrics = []string{"version", "points", "downloads", "likes", "popularity", "dependents", "health"}
+
+// 组装单个 package 指定指标的 shields.io endpoint 内容
+//
+// 参数:
+//   - [packageInfo] package 信息
+//   - [metric]      指标，见 [BadgeMetrics]
+//
+// 返回值:
+//   - [ShieldsEndpoint] 内容
+//   - 指标是否支持
+func AssembleShieldsEndpoint(packageInfo ohpm.PackageInfo, metric string) (ShieldsEndpoint, bool) {
+	endpoint := ShieldsEndpoint{SchemaVersion: 1, Label: "ohpm " + metric}
+	if packageInfo.Code == 0 {
+		endpoint.Message = "not found"
+		endpoint.Color = "lightgrey"
+	}
+	switch metric {
+	case "version":
+		if packageInfo.Code == 1 {
+			endpoint.Message = "v" + packageInfo.Version
+			endpoint.Color = "168AFD"
+		}
+	case "points":
+		if packageInfo.Code == 1 {
+			endpoint.Message = strconv.Itoa(packageInfo.Points) + "/" + strconv.Itoa(packageInfo.MaxPoints)
+			endpoint.Color = PointsColor(packageInfo.Points, packageInfo.MaxPoints)
+		}
+	case "downloads":
+		if packageInfo.Code == 1 {
+			endpoint.Message = FormatNumber(packageInfo.Downloads)
+			endpoint.Color = "4AC51C"
+		}
+	case "likes":
+		if packageInfo.Code == 1 {
+			endpoint.Message = FormatNumber(packageInfo.Likes)
+			endpoint.Color = "168AFD"
+		}
+	case "popularity":
+		if packageInfo.Code == 1 {
+			endpoint.Message = FormatNumber(packageInfo.Popularity)
+			endpoint.Color = "4AC51C"
+		}
+	case "dependents":
+		if packageInfo.Code == 1 {
+			endpoint.Message = FormatNumber(packageInfo.Dependents)
+			endpoint.Color = "168AFD"
+		}
+	case "health":
+		if packageInfo.Code == 1 {
+			endpoint.Message = strconv.Itoa(packageInfo.HealthScore)
+			endpoint.Color = HealthColor(packageInfo.HealthScore)
+		}
+	default:
+		return ShieldsEndpoint{}, false
+	}
+	return endpoint, true
+}
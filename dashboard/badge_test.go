@@ -0,0 +1,62 @@
+package dashboard
+
+import (
+	"testing"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestAssembleShieldsEndpoint(t *testing.T) {
+	info := ohpm.PackageInfo{Code: 1, Name: "@a/b", Version: "1.2.0", Points: 30, MaxPoints: 50, Downloads: 1200, Likes: 3, Popularity: 44, Dependents: 2, HealthScore: 72}
+	tests := []struct {
+		metric string
+		want   ShieldsEndpoint
+	}{
+		{"version", ShieldsEndpoint{1, "ohpm version", "v1.2.0", "168AFD"}},
+		{"points", ShieldsEndpoint{1, "ohpm points", "30/50", "95C30D"}},
+		{"downloads", ShieldsEndpoint{1, "ohpm downloads", "1.2k", "4AC51C"}},
+		{"likes", ShieldsEndpoint{1, "ohpm likes", "3", "168AFD"}},
+		{"popularity", ShieldsEndpoint{1, "ohpm popularity", "44", "4AC51C"}},
+		{"dependents", ShieldsEndpoint{1, "ohpm dependents", "2", "168AFD"}},
+		{"health", ShieldsEndpoint{1, "ohpm health", "72", "D6AE22"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			got, ok := AssembleShieldsEndpoint(info, tt.metric)
+			if !ok || got != tt.want {
+				t.Errorf("got (%+v, %v), want %+v", got, ok, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown metric", func(t *testing.T) {
+		if _, ok := AssembleShieldsEndpoint(info, "stars"); ok {
+			t.Error("ok = true, want false")
+		}
+	})
+
+	t.Run("package not found", func(t *testing.T) {
+		got, ok := AssembleShieldsEndpoint(ohpm.PackageInfo{Code: 0, Name: "x"}, "points")
+		if !ok || got.Message != "not found" || got.Color != "lightgrey" {
+			t.Errorf("got (%+v, %v)", got, ok)
+		}
+	})
+}
+
+func TestPointsColor(t *testing.T) {
+	tests := []struct {
+		points, maxPoints int
+		want              string
+	}{
+		{50, 50, "4AC51C"},
+		{49, 50, "95C30D"},
+		{24, 50, "9FA226"},
+		{9, 50, "D6AE22"},
+		{4, 50, "D66049"},
+	}
+	for _, tt := range tests {
+		if got := PointsColor(tt.points, tt.maxPoints); got != tt.want {
+			t.Errorf("PointsColor(%d, %d) = %q, want %q", tt.points, tt.maxPoints, got, tt.want)
+		}
+	}
+}
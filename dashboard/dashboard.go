@@ -0,0 +1,129 @@
+// Package dashboard 将 [ohpm.PackageInfo] 列表渲染为仪表盘内容：
+// Markdown 表格、版本历史、依赖关系图、Publisher 汇总、需要关注的 package、HTML 页面、
+// shields.io 徽章与 Atom feed，并计算健康度、检查 license。
+//
+// 使用:
+//
+//	packageInfoList, err := dashboard.Fetch(ctx, ohpm.NewClient(githubToken), dashboard.Config{PublisherList: "6542179b6dad4e55f6635764", SortField: "name", HealthWeights: dashboard.DefaultHealthWeights})
+//	markdown := dashboard.AssembleMarkdownTable(packageInfoList, "name")
+//	err = mdinject.UpdateFile("README.md", dashboard.BlockTable, markdown)
+package dashboard
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// Markdown 占位名称，见 mdinject 包
+const (
+	BlockTable           = "OHPMDashboard"                 // 仪表盘表格
+	BlockTotal           = "OHPMDashboard-total"           // Package 数量
+	BlockChangelog       = "OHPMDashboard-changelog"       // 每个 Package 的版本历史
+	BlockDependencyGraph = "OHPMDashboard-dependencyGraph" // Package 之间的依赖关系图（Mermaid）
+	BlockPublishers      = "OHPMDashboard-publishers"      // Publisher 汇总
+	BlockAttention       = "OHPMDashboard-attention"       // 需要关注的 Package（健康度较低）
+)
+
+// 仪表盘配置：package 来源与排序方式
+type Config struct {
+	Name             string `json:"name"`
+	PublisherList    string `json:"publisherList"`
+	PackageList      string `json:"packageList"`
+	SearchQuery      string `json:"searchQuery"`
+	SearchMax        int    `json:"searchMax"`
+	SortField        string `json:"sortField"`
+	SortMode         string `json:"sortMode"`
+	HealthWeights    string `json:"healthWeights"`
+	LicenseAllowlist string `json:"licenseAllowlist"`
+}
+
+// 抓取并排序单个仪表盘的全部 package 信息
+//
+// 参数:
+//   - [ctx]    上下文
+//   - [client] ohpm 客户端
+//   - [config] 仪表盘配置
+//
+// 返回值:
+//   - 排序后的 [ohpm.PackageInfo] 列表
+func Fetch(ctx context.Context, client *ohpm.Client, config Config) ([]ohpm.PackageInfo, error) {
+	// 抓取前校验，避免抓取完成后才因参数错误失败
+	if _, err := ParseHealthWeights(config.HealthWeights); err != nil {
+		return nil, err
+	}
+	packageNames, err := client.MergePackageList(ctx, config.PublisherList, config.PackageList, config.SearchQuery, config.SearchMax)
+	if err != nil {
+		return nil, err
+	}
+	packageInfoList, err := client.FetchPackages(ctx, packageNames)
+	if err != nil {
+		return nil, err
+	}
+	return Prepare(packageInfoList, config)
+}
+
+// 计算健康度、检查 license 并排序（无网络请求，抓取结果与快照共用）
+//
+// 参数:
+//   - [packageInfoList] 信息列表
+//   - [config]          仪表盘配置
+//
+// 返回值:
+//   - 排序后的 [ohpm.PackageInfo] 列表
+func Prepare(packageInfoList []ohpm.PackageInfo, config Config) ([]ohpm.PackageInfo, error) {
+	healthWeights, err := ParseHealthWeights(config.HealthWeights)
+	if err != nil {
+		return nil, err
+	}
+	ApplyHealthScores(packageInfoList, healthWeights, time.Now())
+	ApplyLicenseChecks(packageInfoList, config.LicenseAllowlist)
+	SortPackageInfo(packageInfoList, config.SortField, config.SortMode)
+	return packageInfoList, nil
+}
+
+// 对 [packageInfoList] 排序
+//
+// 参数:
+//   - [packageInfoList]  信息列表
+//   - [sortField]        排序字段 可选：name(default) | publishTime | ohpmLikes | ohpmDownloads | ohpmDependents | githubStars | healthScore
+//   - [sortMode]         排序方式 可选：asc(default) | desc
+func SortPackageInfo(packageInfoList []ohpm.PackageInfo, sortField string, sortMode string) {
+	isDesc := sortMode == "desc"
+	sort.SliceStable(packageInfoList, func(i, j int) bool {
+		p1 := packageInfoList[i]
+		p2 := packageInfoList[j]
+		var result bool
+		switch sortField {
+		case "name":
+			// 按照 名称 排序
+			result = p1.Name < p2.Name
+		case "publishTime":
+			// 按 最新发布时间 排序
+			result = p1.PublishTime > p2.PublishTime
+		case "ohpmLikes":
+			// 按 ohpm likes 排序
+			result = p1.Likes < p2.Likes
+		case "ohpmDownloads":
+			// 按 ohpm downloads 排序
+			result = p1.Downloads < p2.Downloads
+		case "ohpmDependents":
+			// 按 ohpm dependents 排序
+			result = p1.Dependents < p2.Dependents
+		case "githubStars":
+			// 按 github stars 排序
+			result = p1.GithubBaseInfo.StargazersCount < p2.GithubBaseInfo.StargazersCount
+		case "healthScore":
+			// 按 健康度 排序
+			result = p1.HealthScore < p2.HealthScore
+		default:
+			result = p1.Name < p2.Name
+		}
+		if isDesc {
+			return !result
+		}
+		return result
+	})
+}
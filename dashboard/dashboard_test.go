@@ -0,0 +1,119 @@
+package dashboard
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestSortPackageInfo(t *testing.T) {
+	names := func(list []ohpm.PackageInfo) []string {
+		out := make([]string, len(list))
+		for i, p := range list {
+			out[i] = p.Name
+		}
+		return out
+	}
+
+	t.Run("by name asc", func(t *testing.T) {
+		list := []ohpm.PackageInfo{{Name: "c"}, {Name: "a"}, {Name: "b"}}
+		SortPackageInfo(list, "name", "asc")
+		if got := names(list); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("by name desc", func(t *testing.T) {
+		list := []ohpm.PackageInfo{{Name: "a"}, {Name: "c"}, {Name: "b"}}
+		SortPackageInfo(list, "name", "desc")
+		if got := names(list); !reflect.DeepEqual(got, []string{"c", "b", "a"}) {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("by githubStars asc", func(t *testing.T) {
+		list := []ohpm.PackageInfo{
+			{Name: "a", GithubBaseInfo: ohpm.GithubBaseInfo{StargazersCount: 30}},
+			{Name: "b", GithubBaseInfo: ohpm.GithubBaseInfo{StargazersCount: 10}},
+			{Name: "c", GithubBaseInfo: ohpm.GithubBaseInfo{StargazersCount: 20}},
+		}
+		SortPackageInfo(list, "githubStars", "asc")
+		if got := names(list); !reflect.DeepEqual(got, []string{"b", "c", "a"}) {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("by ohpmDownloads desc", func(t *testing.T) {
+		list := []ohpm.PackageInfo{
+			{Name: "a", Downloads: 100},
+			{Name: "b", Downloads: 300},
+			{Name: "c", Downloads: 200},
+		}
+		SortPackageInfo(list, "ohpmDownloads", "desc")
+		if got := names(list); !reflect.DeepEqual(got, []string{"b", "c", "a"}) {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("by ohpmLikes asc", func(t *testing.T) {
+		list := []ohpm.PackageInfo{
+			{Name: "a", Likes: 30},
+			{Name: "b", Likes: 10},
+			{Name: "c", Likes: 20},
+		}
+		SortPackageInfo(list, "ohpmLikes", "asc")
+		if got := names(list); !reflect.DeepEqual(got, []string{"b", "c", "a"}) {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("by ohpmDependents desc", func(t *testing.T) {
+		list := []ohpm.PackageInfo{
+			{Name: "a", Dependents: 1},
+			{Name: "b", Dependents: 5},
+			{Name: "c", Dependents: 3},
+		}
+		SortPackageInfo(list, "ohpmDependents", "desc")
+		if got := names(list); !reflect.DeepEqual(got, []string{"b", "c", "a"}) {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("by healthScore asc puts packages needing attention first", func(t *testing.T) {
+		list := []ohpm.PackageInfo{
+			{Name: "a", HealthScore: 90},
+			{Name: "b", HealthScore: 20},
+			{Name: "c", HealthScore: 55},
+		}
+		SortPackageInfo(list, "healthScore", "asc")
+		if got := names(list); !reflect.DeepEqual(got, []string{"b", "c", "a"}) {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("by publishTime desc means newest first", func(t *testing.T) {
+		list := []ohpm.PackageInfo{
+			{Name: "old", PublishTime: 100},
+			{Name: "new", PublishTime: 300},
+			{Name: "mid", PublishTime: 200},
+		}
+		SortPackageInfo(list, "publishTime", "desc")
+		if got := names(list); !reflect.DeepEqual(got, []string{"old", "mid", "new"}) {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("stable for equal values", func(t *testing.T) {
+		// All stars equal -> input order must be preserved (deterministic output).
+		list := []ohpm.PackageInfo{
+			{Name: "x", GithubBaseInfo: ohpm.GithubBaseInfo{StargazersCount: 5}},
+			{Name: "y", GithubBaseInfo: ohpm.GithubBaseInfo{StargazersCount: 5}},
+			{Name: "z", GithubBaseInfo: ohpm.GithubBaseInfo{StargazersCount: 5}},
+		}
+		SortPackageInfo(list, "githubStars", "asc")
+		if got := names(list); !reflect.DeepEqual(got, []string{"x", "y", "z"}) {
+			t.Errorf("expected stable order x,y,z, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package dashboard
+
+import (
+	"encoding/xml"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// FeedEntryLimit 是 Atom feed 中最多保留的版本数量（按发布时间倒序）
+const FeedEntryLimit = 50
+
+// Atom feed（RFC 4287）
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	Id      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    AtomLink    `xml:"link"`
+	Author  AtomAuthor  `xml:"author"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type AtomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type AtomEntry struct {
+	Title   string   `xml:"title"`
+	Id      string   `xml:"id"`
+	Updated string   `xml:"updated"`
+	Link    AtomLink `xml:"link"`
+	Summary string   `xml:"summary,omitempty"`
+}
+
+// 组装最近发布版本的 Atom feed
+//
+// 每个 package 的每个版本一条 entry；无版本历史时以最新版本代替。
+//
+// 参数:
+//   - [title]           feed 标题
+//   - [packageInfoList] 信息列表
+//   - [now]             无任何版本时作为 feed 更新时间
+//
+// 返回值:
+//   - Atom XML 内容
+func AssembleAtomFeed(title string, packageInfoList []ohpm.PackageInfo, now time.Time) ([]byte, error) {
+	type release struct {
+		packageInfo ohpm.PackageInfo
+		version     ohpm.PackageVersion
+	}
+	releases := []release{}
+	for _, value := range packageInfoList {
+		if value.Code == 0 {
+			continue
+		}
+		versions := value.Versions
+		if len(versions) == 0 && value.Version != "" {
+			versions = []ohpm.PackageVersion{{Version: value.Version, PublishTime: value.PublishTime}}
+		}
+		for _, version := range versions {
+			releases = append(releases, release{value, version})
+		}
+	}
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].version.PublishTime > releases[j].version.PublishTime
+	})
+	if len(releases) > FeedEntryLimit {
+		releases = releases[:FeedEntryLimit]
+	}
+
+	formatTime := func(millisecondTimestamp int) string {
+		return time.UnixMilli(int64(millisecondTimestamp)).UTC().Format(time.RFC3339)
+	}
+	feed := AtomFeed{
+		Title:   title,
+		Id:      "urn:ohpm-dashboard:" + url.PathEscape(title),
+		Updated: now.UTC().Format(time.RFC3339),
+		Link:    AtomLink{Href: "https://github.com/AmosHuKe/ohpm-dashboard"},
+		Author:  AtomAuthor{Name: "ohpm-dashboard"},
+		Entries: []AtomEntry{},
+	}
+	if len(releases) > 0 {
+		feed.Updated = formatTime(releases[0].version.PublishTime)
+	}
+	for _, value := range releases {
+		summary := value.packageInfo.Description
+		if value.version.Deprecated {
+			summary = "⚠️ deprecated. " + summary
+		}
+		feed.Entries = append(feed.Entries, AtomEntry{
+			Title:   value.packageInfo.Name + " v" + value.version.Version,
+			Id:      "urn:ohpm-dashboard:" + url.PathEscape(value.packageInfo.Name) + ":" + url.PathEscape(value.version.Version),
+			Updated: formatTime(value.version.PublishTime),
+			Link:    AtomLink{Href: value.packageInfo.DetailURL()},
+			Summary: summary,
+		})
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,70 @@
+package dashboard
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestAssembleAtomFeed(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	list := []ohpm.PackageInfo{
+		{Code: 1, Name: "@a/b", Description: "desc", Versions: []ohpm.PackageVersion{
+			{Version: "1.1.0", PublishTime: 3000},
+			{Version: "1.0.0", PublishTime: 1000, Deprecated: true},
+		}},
+		// 无版本历史 -> 以最新版本代替
+		{Code: 1, Name: "@a/c", Version: "2.0.0", PublishTime: 2000},
+		{Code: 0, Name: "missing"},
+	}
+	data, err := AssembleAtomFeed("releases", list, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(string(data), xml.Header) {
+		t.Error("missing xml header")
+	}
+
+	var feed AtomFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("invalid xml: %v", err)
+	}
+	titles := []string{}
+	for _, entry := range feed.Entries {
+		titles = append(titles, entry.Title)
+	}
+	if strings.Join(titles, ",") != "@a/b v1.1.0,@a/c v2.0.0,@a/b v1.0.0" {
+		t.Errorf("entries = %v, want newest first", titles)
+	}
+	if feed.Updated != time.UnixMilli(3000).UTC().Format(time.RFC3339) {
+		t.Errorf("feed updated = %q, want newest release time", feed.Updated)
+	}
+	if !strings.HasPrefix(feed.Entries[2].Summary, "⚠️ deprecated") {
+		t.Errorf("summary = %q", feed.Entries[2].Summary)
+	}
+	if feed.Entries[0].Id == feed.Entries[2].Id {
+		t.Error("entry ids must be unique per version")
+	}
+}
+
+func TestAssembleAtomFeedLimit(t *testing.T) {
+	versions := make([]ohpm.PackageVersion, FeedEntryLimit+10)
+	for i := range versions {
+		versions[i] = ohpm.PackageVersion{Version: "1.0." + strconv.Itoa(i), PublishTime: i}
+	}
+	data, err := AssembleAtomFeed("releases", []ohpm.PackageInfo{{Code: 1, Name: "a", Versions: versions}}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var feed AtomFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatal(err)
+	}
+	if len(feed.Entries) != FeedEntryLimit {
+		t.Errorf("got %d entries, want %d", len(feed.Entries), FeedEntryLimit)
+	}
+}
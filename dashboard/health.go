@@ -0,0 +1,213 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// 健康度：综合 ohpm 与 Github 信号得出的 0~100 分，用于找出需要关注的 package
+//
+// 评分项（各项 0~1，按权重加权平均；不适用的项不参与计算，如无 Github 仓库时的 issues/archived/contributors）:
+//   - `points`       ohpm points 占满分的比例
+//   - `freshness`    距最新发布的天数，180 天内满分，730 天以上为 0
+//   - `issues`       open issues 相对 stars 的比例，越少越好
+//   - `license`      ohpm 或 Github 是否声明了 license
+//   - `archived`     Github 仓库是否已归档
+//   - `contributors` Github 贡献者数量，3 人及以上满分
+const DefaultHealthWeights = "points=30,freshness=20,issues=15,license=10,archived=15,contributors=10"
+
+// 健康度低于该分数的 package 会出现在需要关注列表中
+const HealthAttentionThreshold = 60
+
+// 支持的健康度评分项
+var HealthFactors = []string{"points", "freshness", "issues", "license", "archived", "contributors"}
+
+// 解析健康度权重
+//
+// 参数:
+//   - [healthWeights] 权重列表（逗号,分割），如 "points=30,freshness=20"，未列出的评分项权重为 0
+//
+// 返回值:
+//   - 评分项 -> 权重
+func ParseHealthWeights(healthWeights string) (map[string]float64, error) {
+	weights := map[string]float64{}
+	for _, item := range ohpm.SplitList(healthWeights) {
+		factor, rawWeight, ok := strings.Cut(item, "=")
+		factor = strings.TrimSpace(factor)
+		weight, err := strconv.ParseFloat(strings.TrimSpace(rawWeight), 64)
+		if !ok || err != nil || weight < 0 || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("❤️❌ ParseHealthWeights: invalid weight %q", item)
+		}
+		known := false
+		for _, value := range HealthFactors {
+			known = known || factor == value
+		}
+		if !known {
+			return nil, fmt.Errorf("❤️❌ ParseHealthWeights: unknown factor %q, want one of %s", factor, strings.Join(HealthFactors, ", "))
+		}
+		weights[factor] = weight
+	}
+	return weights, nil
+}
+
+// 计算单个 package 的健康度
+//
+// 参数:
+//   - [packageInfo] package 信息
+//   - [weights]     评分项权重
+//   - [now]         当前时间
+//
+// 返回值:
+//   - 健康度 0~100（无法获取信息的 package 为 0）
+//   - 拉低健康度的原因（单项低于 0.5 分，或满分不足的 points）
+func HealthScore(packageInfo ohpm.PackageInfo, weights map[string]float64, now time.Time) (int, []string) {
+	if packageInfo.Code == 0 {
+		return 0, []string{"not found"}
+	}
+	// 未获取 Github 信息（匿名访问额度耗尽）时，Github 相关评分项不参与计算
+	hasGithub := packageInfo.GithubUser != "" && packageInfo.GithubRepo != "" && !packageInfo.GithubSkipped
+	reasons := []string{}
+	var total, weightTotal float64
+	factor := func(name string, applicable bool, value float64, reason string) {
+		if !applicable || weights[name] == 0 {
+			return
+		}
+		total += weights[name] * value
+		weightTotal += weights[name]
+		if reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+
+	// points
+	if packageInfo.MaxPoints > 0 {
+		value := math.Min(1, float64(packageInfo.Points)/float64(packageInfo.MaxPoints))
+		reason := ""
+		if value < 1 {
+			reason = "points " + strconv.Itoa(packageInfo.Points) + "/" + strconv.Itoa(packageInfo.MaxPoints)
+		}
+		factor("points", true, value, reason)
+	}
+	// freshness
+	if packageInfo.PublishTime > 0 {
+		days := daysSince(packageInfo.PublishTime, now)
+		value := math.Max(0, math.Min(1, float64(730-days)/float64(730-180)))
+		reason := ""
+		if value < 0.5 {
+			reason = "last release " + strconv.Itoa(days) + "d ago"
+		}
+		factor("freshness", true, value, reason)
+	}
+	// license
+	if packageInfo.LicenseName != "" || packageInfo.GithubBaseInfo.License.Name != "" {
+		factor("license", true, 1, "")
+	} else {
+		factor("license", true, 0, "no license")
+	}
+	// issues / archived / contributors
+	if hasGithub && packageInfo.GithubBaseInfo.NotFound {
+		// 仓库不存在（404）-> Github 相关评分项均为 0
+		for _, name := range []string{"issues", "archived", "contributors"} {
+			factor(name, true, 0, "")
+		}
+		reasons = append(reasons, "no repo")
+	} else if hasGithub {
+		baseInfo := packageInfo.GithubBaseInfo
+		value := 1 - math.Min(1, float64(baseInfo.OpenIssuesCount)/float64(baseInfo.StargazersCount+1))
+		reason := ""
+		if value < 0.5 {
+			reason = strconv.Itoa(baseInfo.OpenIssuesCount) + " open issues / " + strconv.Itoa(baseInfo.StargazersCount) + " stars"
+		}
+		factor("issues", true, value, reason)
+
+		if baseInfo.Archived {
+			factor("archived", true, 0, "archived repo")
+		} else {
+			factor("archived", true, 1, "")
+		}
+
+		value = math.Min(1, float64(baseInfo.ContributorsTotal)/3)
+		reason = ""
+		if value < 0.5 {
+			reason = strconv.Itoa(baseInfo.ContributorsTotal) + " contributor(s)"
+		}
+		factor("contributors", true, value, reason)
+	}
+
+	if weightTotal == 0 {
+		return 0, reasons
+	}
+	return int(math.Round(100 * total / weightTotal)), reasons
+}
+
+// 按健康度获取徽章颜色（低于 [HealthAttentionThreshold] 为红色）
+//
+// 参数:
+//   - [score] 健康度 0~100
+//
+// 返回值:
+//   - 颜色
+func HealthColor(score int) string {
+	switch {
+	case score >= 80:
+		return "4AC51C"
+	case score >= HealthAttentionThreshold:
+		return "D6AE22"
+	default:
+		return "D66049"
+	}
+}
+
+// 计算并写入所有 package 的健康度
+//
+// 参数:
+//   - [packageInfoList] 信息列表
+//   - [weights]         评分项权重
+//   - [now]             当前时间
+func ApplyHealthScores(packageInfoList []ohpm.PackageInfo, weights map[string]float64, now time.Time) {
+	for i := range packageInfoList {
+		packageInfoList[i].HealthScore, packageInfoList[i].HealthReasons = HealthScore(packageInfoList[i], weights, now)
+	}
+}
+
+// 组装需要关注的 package 列表（无法获取信息、Github 仓库不存在或健康度低于 [HealthAttentionThreshold]，按健康度升序）
+//
+// 参数:
+//   - [packageInfoList] 信息列表（需先 [ApplyHealthScores]）
+//
+// 返回值:
+//   - markdown 列表内容
+func AssembleMarkdownAttention(packageInfoList []ohpm.PackageInfo) string {
+	attention := []ohpm.PackageInfo{}
+	for _, value := range packageInfoList {
+		if value.Code == 0 || value.GithubBaseInfo.NotFound || value.HealthScore < HealthAttentionThreshold {
+			attention = append(attention, value)
+		}
+	}
+	if len(attention) == 0 {
+		return "<sub>All packages are healthy</sub>"
+	}
+	sort.SliceStable(attention, func(i, j int) bool {
+		if attention[i].HealthScore != attention[j].HealthScore {
+			return attention[i].HealthScore < attention[j].HealthScore
+		}
+		return attention[i].Name < attention[j].Name
+	})
+
+	markdown := ""
+	for _, value := range attention {
+		markdown += "- [" + value.Name + "](" + value.DetailURL() + ") " +
+			"<sub><strong>" + strconv.Itoa(value.HealthScore) + "</strong>"
+		for _, reason := range value.HealthReasons {
+			markdown += " · " + FormatString(reason)
+		}
+		markdown += "</sub>\n"
+	}
+	return markdown
+}
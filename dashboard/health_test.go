@@ -0,0 +1,120 @@
+package dashboard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestParseHealthWeights(t *testing.T) {
+	got, err := ParseHealthWeights("points=30, freshness = 20,license=0.5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]float64{"points": 30, "freshness": 20, "license": 0.5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, err := ParseHealthWeights(DefaultHealthWeights); err != nil {
+		t.Errorf("default weights: %v", err)
+	}
+	for _, input := range []string{"stars=10", "points", "points=abc", "points=-1"} {
+		if _, err := ParseHealthWeights(input); err == nil {
+			t.Errorf("ParseHealthWeights(%q) expected error", input)
+		}
+	}
+}
+
+func TestHealthScore(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) int { return int(now.AddDate(0, 0, -days).UnixMilli()) }
+	weights, _ := ParseHealthWeights(DefaultHealthWeights)
+
+	t.Run("healthy package", func(t *testing.T) {
+		info := ohpm.PackageInfo{Code: 1, Points: 100, MaxPoints: 100, PublishTime: daysAgo(10), LicenseName: "MIT",
+			GithubUser: "u", GithubRepo: "r", GithubBaseInfo: ohpm.GithubBaseInfo{StargazersCount: 50, OpenIssuesCount: 2, ContributorsTotal: 5}}
+		score, reasons := HealthScore(info, weights, now)
+		if score != 99 || len(reasons) != 0 {
+			t.Errorf("got (%d, %v)", score, reasons)
+		}
+	})
+
+	t.Run("neglected package", func(t *testing.T) {
+		info := ohpm.PackageInfo{Code: 1, Points: 40, MaxPoints: 100, PublishTime: daysAgo(800),
+			GithubUser: "u", GithubRepo: "r", GithubBaseInfo: ohpm.GithubBaseInfo{StargazersCount: 1, OpenIssuesCount: 9, ContributorsTotal: 1}}
+		info.GithubBaseInfo.Archived = true
+		score, reasons := HealthScore(info, weights, now)
+		if score != 15 {
+			t.Errorf("score = %d, want 15", score)
+		}
+		want := []string{"points 40/100", "last release 800d ago", "no license", "9 open issues / 1 stars", "archived repo", "1 contributor(s)"}
+		if !reflect.DeepEqual(reasons, want) {
+			t.Errorf("reasons = %v, want %v", reasons, want)
+		}
+	})
+
+	t.Run("github factors skipped without repo", func(t *testing.T) {
+		info := ohpm.PackageInfo{Code: 1, Points: 100, MaxPoints: 100, PublishTime: daysAgo(10), LicenseName: "MIT"}
+		if score, _ := HealthScore(info, weights, now); score != 100 {
+			t.Errorf("score = %d, want 100", score)
+		}
+	})
+
+	t.Run("github factors skipped when github data was not fetched", func(t *testing.T) {
+		info := ohpm.PackageInfo{Code: 1, Points: 100, MaxPoints: 100, PublishTime: daysAgo(10), LicenseName: "MIT", GithubUser: "u", GithubRepo: "r", GithubSkipped: true}
+		if score, _ := HealthScore(info, weights, now); score != 100 {
+			t.Errorf("score = %d, want 100", score)
+		}
+	})
+
+	t.Run("missing github repo", func(t *testing.T) {
+		info := ohpm.PackageInfo{Code: 1, Points: 100, MaxPoints: 100, PublishTime: daysAgo(10), LicenseName: "MIT",
+			GithubUser: "u", GithubRepo: "r", GithubBaseInfo: ohpm.GithubBaseInfo{NotFound: true}}
+		score, reasons := HealthScore(info, weights, now)
+		if score != 60 || !reflect.DeepEqual(reasons, []string{"no repo"}) {
+			t.Errorf("got (%d, %v)", score, reasons)
+		}
+	})
+
+	t.Run("only weighted factors count", func(t *testing.T) {
+		info := ohpm.PackageInfo{Code: 1, Points: 50, MaxPoints: 100}
+		if score, _ := HealthScore(info, map[string]float64{"license": 1}, now); score != 0 {
+			t.Errorf("score = %d, want 0", score)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		score, reasons := HealthScore(ohpm.PackageInfo{Code: 0}, weights, now)
+		if score != 0 || !reflect.DeepEqual(reasons, []string{"not found"}) {
+			t.Errorf("got (%d, %v)", score, reasons)
+		}
+	})
+}
+
+func TestAssembleMarkdownAttention(t *testing.T) {
+	if got := AssembleMarkdownAttention([]ohpm.PackageInfo{{Code: 1, Name: "a", HealthScore: 80}}); got != "<sub>All packages are healthy</sub>" {
+		t.Errorf("healthy = %q", got)
+	}
+	got := AssembleMarkdownAttention([]ohpm.PackageInfo{
+		{Code: 1, Name: "@a/ok", HealthScore: 90},
+		{Code: 1, Name: "@a/low", HealthScore: 45, HealthReasons: []string{"archived repo", "no license"}},
+		{Code: 0, Name: "@a/gone", HealthReasons: []string{"not found"}},
+	})
+	want := "- [@a/gone](https://ohpm.openharmony.cn/#/cn/detail/@a%2Fgone) <sub><strong>0</strong> · not found</sub>\n" +
+		"- [@a/low](https://ohpm.openharmony.cn/#/cn/detail/@a%2Flow) <sub><strong>45</strong> · archived repo · no license</sub>\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if strings.Contains(got, "@a/ok") {
+		t.Error("healthy package listed")
+	}
+
+	// Github 仓库不存在时即使健康度不低也需要关注
+	missing := ohpm.PackageInfo{Code: 1, Name: "@a/moved", HealthScore: 60, HealthReasons: []string{"no repo"}, GithubBaseInfo: ohpm.GithubBaseInfo{NotFound: true}}
+	if got := AssembleMarkdownAttention([]ohpm.PackageInfo{missing}); !strings.Contains(got, "@a/moved") || !strings.Contains(got, "no repo") {
+		t.Errorf("missing repo not listed: %q", got)
+	}
+}
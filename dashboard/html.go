@@ -0,0 +1,85 @@
+package dashboard
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// 组装完整 HTML 页面
+//
+// 参数:
+//   - [title]     页面标题
+//   - [markdown]  [AssembleMarkdownTable] 生成的表格内容
+//   - [updatedAt] 数据更新时间
+func AssembleHTMLPage(title string, markdown string, updatedAt time.Time) string {
+	title = html.EscapeString(title)
+	return "<!DOCTYPE html>\n" +
+		`<html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1">` +
+		"<title>ohpm-dashboard: " + title + "</title>" +
+		"<style>body{font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;margin:2em}table{border-collapse:collapse}td,th{border:1px solid #d0d7de;padding:6px 13px;vertical-align:top}td table td{border:0}</style>" +
+		"</head><body>\n" +
+		"<h1>" + title + "</h1>\n" +
+		markdownTableToHTML(markdown) +
+		"<p><sub>Updated on " + updatedAt.Format(time.RFC3339) + ` by <a href="https://github.com/AmosHuKe/ohpm-dashboard">ohpm-dashboard</a>.</sub></p>` + "\n" +
+		"</body></html>\n"
+}
+
+var (
+	// [![alt](src)](href)
+	markdownImageLinkRegexp = regexp.MustCompile(`\[!\[([^\]]*)\]\(([^)\s]*)\)\]\(([^)\s]*)\)`)
+	// [text](href)
+	markdownLinkRegexp = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// 将 [AssembleMarkdownTable] 生成的 Markdown 转换为 HTML
+//
+// 仅处理该表格用到的语法：表格行、图片链接、普通链接。
+// 单元格中的第三方文本已由 [FormatString] 转义，其余 HTML 原样保留。
+//
+// 参数:
+//   - [markdown] 表格内容
+//
+// 返回值:
+//   - HTML 内容
+func markdownTableToHTML(markdown string) string {
+	inline := func(v string) string {
+		v = markdownImageLinkRegexp.ReplaceAllString(v, `<a href="$3"><img alt="$1" src="$2" /></a>`)
+		return markdownLinkRegexp.ReplaceAllString(v, `<a href="$2">$1</a>`)
+	}
+	cells := func(line string) []string {
+		line = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(line), "|"), "|")
+		values := strings.Split(line, "|")
+		for i, value := range values {
+			values[i] = inline(strings.TrimSpace(value))
+		}
+		return values
+	}
+
+	out := strings.Builder{}
+	rowIndex := 0
+	for _, line := range strings.Split(markdown, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case !strings.HasPrefix(line, "|"):
+			out.WriteString("<p>" + inline(line) + "</p>\n")
+			continue
+		}
+		switch rowIndex {
+		case 0:
+			out.WriteString("<table>\n<thead><tr><th>" + strings.Join(cells(line), "</th><th>") + "</th></tr></thead>\n<tbody>\n")
+		case 1:
+			// 分隔行
+		default:
+			out.WriteString("<tr><td>" + strings.Join(cells(line), "</td><td>") + "</td></tr>\n")
+		}
+		rowIndex++
+	}
+	if rowIndex > 0 {
+		out.WriteString("</tbody>\n</table>\n")
+	}
+	return out.String()
+}
@@ -0,0 +1,24 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownTableToHTML(t *testing.T) {
+	markdown := "<sub>Sort by name | Total 1</sub> \n\n" +
+		"| <sub>Package</sub> | <sub>Stars</sub> | \n" +
+		"|----|----| \n" +
+		"| [a](https://x/a) <br/> <sub>desc &lt;b&gt;</sub> | [![stars](https://img/s.svg)](https://x/s) | \n"
+	got := markdownTableToHTML(markdown)
+	for _, want := range []string{
+		"<p><sub>Sort by name | Total 1</sub></p>",
+		"<th><sub>Package</sub></th><th><sub>Stars</sub></th>",
+		`<td><a href="https://x/a">a</a> <br/> <sub>desc &lt;b&gt;</sub></td>`,
+		`<td><a href="https://x/s"><img alt="stars" src="https://img/s.svg" /></a></td>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package dashboard
+
+import (
+	"strings"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// License 检查类型
+const (
+	LicenseIssueMissing    = "missing"    // ohpm 未声明 license
+	LicenseIssueNotAllowed = "notAllowed" // license 不在允许列表中
+	LicenseIssueMismatch   = "mismatch"   // ohpm 与 Github 仓库的 license 不一致
+)
+
+// 检查单个 package 的 license
+//
+// 以 ohpm 声明的 license 为准；Github 的 license 同时与 SPDX ID 和名称比较（忽略大小写），
+// Github 无法识别的 license（NOASSERTION）不参与比较。
+//
+// 参数:
+//   - [packageInfo] package 信息
+//   - [allowlist]   允许的 SPDX ID 列表（为空时不检查）
+//
+// 返回值:
+//   - [ohpm.LicenseIssue] 列表（无法获取信息的 package 不检查）
+func CheckLicense(packageInfo ohpm.PackageInfo, allowlist []string) []ohpm.LicenseIssue {
+	issues := []ohpm.LicenseIssue{}
+	if packageInfo.Code == 0 {
+		return issues
+	}
+	ohpmLicense := strings.TrimSpace(packageInfo.LicenseName)
+	if ohpmLicense == "" {
+		return append(issues, ohpm.LicenseIssue{Kind: LicenseIssueMissing, Message: "no license on ohpm"})
+	}
+
+	if len(allowlist) > 0 {
+		allowed := false
+		for _, value := range allowlist {
+			allowed = allowed || strings.EqualFold(ohpmLicense, value)
+		}
+		if !allowed {
+			issues = append(issues, ohpm.LicenseIssue{Kind: LicenseIssueNotAllowed, Message: ohpmLicense + " not allowed"})
+		}
+	}
+
+	githubLicense := packageInfo.GithubBaseInfo.License
+	if githubLicense.SpdxId != "" && githubLicense.SpdxId != "NOASSERTION" &&
+		!strings.EqualFold(ohpmLicense, githubLicense.SpdxId) && !strings.EqualFold(ohpmLicense, githubLicense.Name) {
+		issues = append(issues, ohpm.LicenseIssue{Kind: LicenseIssueMismatch, Message: "GitHub: " + githubLicense.SpdxId})
+	}
+	return issues
+}
+
+// 检查并写入所有 package 的 license 问题
+//
+// 参数:
+//   - [packageInfoList]  信息列表
+//   - [licenseAllowlist] 允许的 SPDX ID 列表（逗号,分割），如 "MIT,Apache-2.0"
+func ApplyLicenseChecks(packageInfoList []ohpm.PackageInfo, licenseAllowlist string) {
+	allowlist := ohpm.SplitList(licenseAllowlist)
+	for i := range packageInfoList {
+		packageInfoList[i].LicenseIssues = CheckLicense(packageInfoList[i], allowlist)
+	}
+}
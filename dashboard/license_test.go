@@ -0,0 +1,69 @@
+package dashboard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestCheckLicense(t *testing.T) {
+	withGithub := func(license string, spdxId string, name string) ohpm.PackageInfo {
+		info := ohpm.PackageInfo{Code: 1, Name: "@a/b", LicenseName: license}
+		info.GithubBaseInfo.License.SpdxId = spdxId
+		info.GithubBaseInfo.License.Name = name
+		return info
+	}
+	allowlist := []string{"MIT", "Apache-2.0"}
+	tests := []struct {
+		name string
+		info ohpm.PackageInfo
+		want []string
+	}{
+		{"allowed and matching", withGithub("Apache-2.0", "Apache-2.0", "Apache License 2.0"), []string{}},
+		{"case-insensitive", withGithub("mit", "MIT", "MIT License"), []string{}},
+		{"matches github license name", withGithub("Apache License 2.0", "Apache-2.0", "Apache License 2.0"), []string{"notAllowed"}},
+		{"missing", withGithub("", "MIT", "MIT License"), []string{"missing"}},
+		{"not allowed", withGithub("GPL-3.0", "", ""), []string{"notAllowed"}},
+		{"mismatch", withGithub("MIT", "Apache-2.0", "Apache License 2.0"), []string{"mismatch"}},
+		{"github noassertion ignored", withGithub("MIT", "NOASSERTION", "Other"), []string{}},
+		{"not found skipped", ohpm.PackageInfo{Code: 0, Name: "x"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kinds := []string{}
+			for _, issue := range CheckLicense(tt.info, allowlist) {
+				kinds = append(kinds, issue.Kind)
+			}
+			if !reflect.DeepEqual(kinds, tt.want) {
+				t.Errorf("got %v, want %v", kinds, tt.want)
+			}
+		})
+	}
+
+	t.Run("empty allowlist only checks presence and mismatch", func(t *testing.T) {
+		if issues := CheckLicense(withGithub("GPL-3.0", "GPL-3.0", "GNU GPL v3"), nil); len(issues) != 0 {
+			t.Errorf("got %v", issues)
+		}
+	})
+}
+
+func TestApplyLicenseChecks(t *testing.T) {
+	list := []ohpm.PackageInfo{
+		{Code: 1, Name: "ok", LicenseName: "MIT"},
+		{Code: 1, Name: "gpl", LicenseName: "GPL-3.0"},
+		{Code: 1, Name: "none"},
+	}
+	ApplyLicenseChecks(list, " MIT, Apache-2.0 ")
+	if len(list[0].LicenseIssues) != 0 || len(list[1].LicenseIssues) != 1 || len(list[2].LicenseIssues) != 1 {
+		t.Fatalf("got %+v", list)
+	}
+	table := AssembleMarkdownTable(list, "name")
+	if !strings.Contains(table, "<strong>License:</strong> GPL-3.0 ⚠️ <em>GPL-3.0 not allowed</em>") {
+		t.Errorf("missing warning marker in table:\n%s", table)
+	}
+	if !strings.Contains(table, "<strong>License:</strong> - ⚠️ <em>no license on ohpm</em>") {
+		t.Errorf("missing warning marker for missing license:\n%s", table)
+	}
+}
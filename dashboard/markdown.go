@@ -0,0 +1,534 @@
+package dashboard
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// markdownTable 用于存储每个 package 在 Markdown 表格中的展示信息
+type markdownTable struct {
+	Name          string
+	Registry      string
+	Version       string
+	VersionAhead  string
+	Description   string
+	Keywords      string
+	LicenseName   string
+	PublishTime   string
+	Releases      string
+	Dependencies  string
+	GithubStars   string
+	OhpmLikes     string
+	OhpmDownloads string
+	Points        string
+	PointsDetail  string
+	Health        string
+	Popularity    string
+	Issues        string
+	PullRequests  string
+	Contributors  string
+}
+
+// 统计 [since] 之后（含）发布的版本数量
+//
+// 参数:
+//   - [versions] 版本列表
+//   - [since]    起始时间
+func countReleasesSince(versions []ohpm.PackageVersion, since time.Time) int {
+	count := 0
+	for _, value := range versions {
+		if int64(value.PublishTime) >= since.UnixMilli() {
+			count++
+		}
+	}
+	return count
+}
+
+// 获取首次发布时间（毫秒时间戳），无版本历史时回退为最新发布时间
+//
+// 参数:
+//   - [packageInfo] package 信息
+func firstPublishTime(packageInfo ohpm.PackageInfo) int {
+	first := packageInfo.PublishTime
+	for _, value := range packageInfo.Versions {
+		if value.PublishTime > 0 && (first == 0 || value.PublishTime < first) {
+			first = value.PublishTime
+		}
+	}
+	return first
+}
+
+// 计算毫秒时间戳距 [now] 的天数
+//
+// 参数:
+//   - [millisecondTimestamp] 毫秒时间戳
+//   - [now]                  当前时间
+func daysSince(millisecondTimestamp int, now time.Time) int {
+	if millisecondTimestamp <= 0 {
+		return 0
+	}
+	return int(now.Sub(time.UnixMilli(int64(millisecondTimestamp))).Hours() / 24)
+}
+
+// 组装表格内容
+//
+// 参数:
+//   - [packageInfoList]  信息列表
+//   - [sortField]        排序字段 可选：name(default) | publishTime | ohpmLikes | ohpmDownloads | ohpmDependents | githubStars | healthScore
+//
+// 返回值:
+//   - markdown 表格内容
+func AssembleMarkdownTable(packageInfoList []ohpm.PackageInfo, sortField string) string {
+	now := time.Now()
+	// 仅在混合多个 registry 时显示 registry 列
+	showRegistry := false
+	for _, value := range packageInfoList {
+		if value.Registry != "" {
+			showRegistry = true
+		}
+	}
+	markdownTableList := []markdownTable{}
+	for _, value := range packageInfoList {
+		var name, version, versionAhead, keywords, licenseName, publishTime, releases, dependencies, githubStars, ohpmLikes, ohpmDownloads, points, pointsDetail, health, popularity, issues, pullRequests, contributors string
+		switch value.Code {
+		case 0:
+			// 无法获取信息
+			name = value.Name + " ⁉️"
+		case 1:
+			// 已获取信息
+			// Base
+			const ohpmLogo = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAABQAAAAUCAMAAAC6V+0/AAAA6lBMVEUAAABswm92x09tw2pCq+xhvItMsM9Qs8FhvIxhvI9Bq+1mvn5rwm9OssdqwnFhvI1FreJTtLdowHdMsM1lvoJvxGJJr9hQssJ6yUNeupdXtq1auKJlvoNCq+tFrONJr9ZlvoJzxVlGreFwxGFQssNauKJMscxeuphErOVlvoN6yUNDq+lIrtpTtLdov3lLsM9hvIxAqvJhvI1ErOZwxGB6yUNTtLhAqvF6yUNwxGJ6yUNlvoJeupdzxVlwxGB6yUNHrt1swm1swm1swmxXtq1Xtq1yxVtpwHZvw2RwxGFnv3tnv3t6yUN6yUPKo5kKAAAATnRSTlMABRQL+Ho1JiMeGxoRCKL+/Pz8+PPz8fHx8Ovk4dPOzszGxcKsqqCYh4F/fXp3d2xoZ2VhWlZRR0dBNTEvLiUhFvy9taGgmI+Nf2loaGciFjA1AAAAo0lEQVQY02MgDfCy6bqqqhtxIIuxq4kLSklL8svo8cDF2OSFVczYOW0MFIT4mKBiXEpi+rxgFre7kwlUUFtAB6aHkZERqlBWzgHM4GDV5GbyNGGw0LJnMGfRgCpjFfFmVlZk9pEwZTBkMYZqZrbmZLCzZWSyYsIqiKLdC6TdV8IU1SJLUTeQRShO4nEWtcRwPJ+jByMWb/LgChBE0LmAg45kAADNURSuaNgr4QAAAABJRU5ErkJggg=="
+			const downloadIcon = "data:image/svg+xml;base64,PHN2ZyB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciIHZpZXdCb3g9IjAgMCAyNCAyNCIgZmlsbD0icmdiYSgyNTUsMjU1LDI1NSwxKSI+PHBhdGggZD0iTTMgMTlIMjFWMjFIM1YxOVpNMTMgOUgyMEwxMiAxN0w0IDlIMTFWMUgxM1Y5WiI+PC9wYXRoPjwvc3ZnPg=="
+			const popularityIcon = "data:image/svg+xml;base64,PHN2ZyB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciIHZpZXdCb3g9IjAgMCAyNCAyNCIgZmlsbD0icmdiYSgyNTUsMjU1LDI1NSwxKSI+PHBhdGggZmlsbD0ibm9uZSIgZD0iTTAgMGgyNHYyNEgweiI+PC9wYXRoPjxwYXRoIGQ9Ik0xMiAyM0M3Ljg1Nzg2IDIzIDQuNSAxOS42NDIxIDQuNSAxNS41QzQuNSAxMy4zNDYyIDUuNDA3ODYgMTEuNDA0NSA2Ljg2MTc5IDEwLjAzNjZDOC4yMDQwMyA4Ljc3Mzc1IDExLjUgNi40OTk1MSAxMSAxLjVDMTcgNS41IDIwIDkuNSAxNCAxNS41QzE1IDE1LjUgMTYuNSAxNS41IDE5IDEzLjAyOTZDMTkuMjY5NyAxMy44MDMyIDE5LjUgMTQuNjM0NSAxOS41IDE1LjVDMTkuNSAxOS42NDIxIDE2LjE0MjEgMjMgMTIgMjNaIj48L3BhdGg+PC9zdmc+"
+			const pointIcon = "data:image/svg+xml;base64,PHN2ZyB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciIHZpZXdCb3g9IjAgMCAyNCAyNCIgZmlsbD0icmdiYSgyNTUsMjU1LDI1NSwxKSI+PHBhdGggZD0iTTEuOTQ2MDcgOS4zMTU0M0MxLjQyMzUzIDkuMTQxMjUgMS40MTk0IDguODYwMjIgMS45NTY4MiA4LjY4MTA4TDIxLjA0MyAyLjMxOTAxQzIxLjU3MTUgMi4xNDI4NSAyMS44NzQ2IDIuNDM4NjYgMjEuNzI2NSAyLjk1Njk0TDE2LjI3MzMgMjIuMDQzMkMxNi4xMjIzIDIyLjU3MTYgMTUuODE3NyAyMi41OSAxNS41OTQ0IDIyLjA4NzZMMTEuOTk5OSAxNEwxNy45OTk5IDYuMDAwMDVMOS45OTk5MiAxMkwxLjk0NjA3IDkuMzE1NDNaIj48L3BhdGg+PC9zdmc+"
+
+			name = "[" + value.Name + "](" + value.DetailURL() + ")"
+			version = "v" + value.Version
+			if len(value.Keywords) > 0 {
+				keywords = "<strong>Keywords:</strong> " + FormatString(strings.Join(value.Keywords, ", "))
+			}
+			licenseName = "<strong>License:</strong> "
+			if value.LicenseName != "" {
+				licenseName += FormatString(value.LicenseName)
+			} else {
+				licenseName += "-"
+			}
+			if len(value.LicenseIssues) > 0 {
+				messages := []string{}
+				for _, issue := range value.LicenseIssues {
+					messages = append(messages, issue.Message)
+				}
+				licenseName += " ⚠️ <em>" + FormatString(strings.Join(messages, "; ")) + "</em>"
+			}
+			publishTime = "<strong>PublishTime:</strong> " + timestampFormat(value.PublishTime)
+			releases = "<strong>Releases:</strong> " + strconv.Itoa(countReleasesSince(value.Versions, now.AddDate(0, 0, -90))) + " in 90d" +
+				" · first " + time.UnixMilli(int64(firstPublishTime(value))).UTC().Format(time.DateOnly) +
+				" · last " + strconv.Itoa(daysSince(value.PublishTime, now)) + "d ago"
+			dependencies = "<strong>Dependents:</strong> " + strconv.Itoa(value.Dependents) + " · <strong>Dependencies:</strong> " + strconv.Itoa(len(value.Dependencies))
+			githubStars = ""
+			ohpmLikes = "[![OHPM likes](https://img.shields.io/badge/" + strconv.Itoa(value.Likes) + "-_?style=social&logo=" + ohpmLogo + "&logoColor=168AFD&label=)](" + value.DetailURL() + ")"
+			ohpmDownloads = "[![OHPM downloads](https://img.shields.io/badge/" + FormatNumber(value.Downloads) + "-4AC51C?style=flat&logo=" + downloadIcon + ")](" + value.DetailURL() + ")"
+			popularity = "[![OHPM popularity](https://img.shields.io/badge/" + FormatNumber(value.Popularity) + "-4AC51C?style=flat&logo=" + popularityIcon + ")](" + value.DetailURL() + ")"
+
+			pointsBackgroundColor := PointsColor(value.Points, value.MaxPoints)
+			pointsText := strconv.Itoa(value.Points) + url.PathEscape("/") + strconv.Itoa(value.MaxPoints)
+			points = "[![OHPM points](https://img.shields.io/badge/" + pointsText + "-" + pointsBackgroundColor + "?style=flat&logo=" + pointIcon + ")](" + value.DetailURL() + ")"
+			pointsDetail = assemblePointsDetail(value.PointItems)
+			health = "[![Health](https://img.shields.io/badge/" + strconv.Itoa(value.HealthScore) + "-" + HealthColor(value.HealthScore) + "?style=flat&label=health)](" + value.DetailURL() + ")"
+			issues = "-"
+			pullRequests = "-"
+
+			// Github
+			if value.GithubUser != "" && value.GithubRepo != "" {
+				githubURL := value.GithubUser + "/" + value.GithubRepo
+				githubRepoURL := value.GithubRepoURL()
+				if value.GithubSkipped {
+					// 匿名访问额度耗尽，无已抓取的数据 -> 仅链接
+					githubStars = "[GitHub](" + githubRepoURL + ")"
+					issues = "[Issues](" + githubRepoURL + "/issues)"
+					pullRequests = "[Pull requests](" + githubRepoURL + "/pulls)"
+				} else if value.IsGithubCom() {
+					githubStars = "[![GitHub stars](https://img.shields.io/github/stars/" + githubURL + "?style=social&logo=github&logoColor=1F2328&label=)](" + githubRepoURL + ")"
+					issues = "[![GitHub issues](https://img.shields.io/github/issues/" + githubURL + "?label=)](" + githubRepoURL + "/issues)"
+					pullRequests = "[![GitHub pull requests](https://img.shields.io/github/issues-pr/" + githubURL + "?label=)](" + githubRepoURL + "/pulls)"
+				} else {
+					// shields.io 无法访问 GitHub Enterprise Server，使用已抓取的数据生成静态徽章
+					githubStars = "[![GitHub stars](https://img.shields.io/badge/" + FormatNumber(value.GithubBaseInfo.StargazersCount) + "-_?style=social&logo=github&logoColor=1F2328&label=)](" + githubRepoURL + ")"
+					issues = "[![GitHub issues](https://img.shields.io/badge/" + strconv.Itoa(value.GithubBaseInfo.OpenIssuesCount) + "_open-yellow)](" + githubRepoURL + "/issues)"
+					pullRequests = "[Pull requests](" + githubRepoURL + "/pulls)"
+				}
+				if ohpm.IsGithubVersionAhead(value) {
+					versionAhead = ` <sup><a href="` + githubRepoURL + `/releases" title="GitHub is ahead of ohpm">🔖 ` + FormatString(value.GithubLatestVersion) + `</a></sup>`
+				}
+
+				// contributors begin
+				if value.GithubSkipped {
+					contributors = `<a href="` + githubRepoURL + `/graphs/contributors">Contributors</a>`
+				}
+				if len(value.GithubContributorsInfo) > 0 {
+					var githubContributorsInfoList = value.GithubContributorsInfo
+					contributors += `<table align="center" border="0">`
+
+					// contributors
+					switch len(value.GithubContributorsInfo) {
+					case 1:
+						contributors += `<tr align="center">`
+						contributors += `<td>`
+						contributors += `<a href="` + githubContributorsInfoList[0].HtmlUrl + `"><img width="36px" src="` + getGithubAvatarUrl(value, githubContributorsInfoList[0]) + `" /></a>`
+						contributors += `</td>`
+						contributors += `</tr>`
+					case 2:
+						contributors += `<tr align="center">`
+						contributors += `<td>`
+						contributors += `<a href="` + githubContributorsInfoList[0].HtmlUrl + `"><img width="30px" src="` + getGithubAvatarUrl(value, githubContributorsInfoList[0]) + `" /></a>`
+						contributors += `</td>`
+						contributors += `<td>`
+						contributors += `<a href="` + githubContributorsInfoList[1].HtmlUrl + `"><img width="30px" src="` + getGithubAvatarUrl(value, githubContributorsInfoList[1]) + `" /></a>`
+						contributors += `</td>`
+						contributors += `</tr>`
+					case 3:
+						contributors += `<tr align="center">`
+						contributors += `<td colspan="2">`
+						contributors += `<a href="` + githubContributorsInfoList[0].HtmlUrl + `"><img width="36px" src="` + getGithubAvatarUrl(value, githubContributorsInfoList[0]) + `" /></a>`
+						contributors += `</td>`
+						contributors += `</tr>`
+						contributors += `<tr align="center">`
+						contributors += `<td>`
+						contributors += `<a href="` + githubContributorsInfoList[1].HtmlUrl + `"><img width="30px" src="` + getGithubAvatarUrl(value, githubContributorsInfoList[1]) + `" /></a>`
+						contributors += `</td>`
+						contributors += `<td>`
+						contributors += `<a href="` + githubContributorsInfoList[2].HtmlUrl + `"><img width="30px" src="` + getGithubAvatarUrl(value, githubContributorsInfoList[2]) + `" /></a>`
+						contributors += `</td>`
+						contributors += `</tr>`
+					}
+
+					// total
+					contributors += `<tr align="center">`
+					contributors += `<td colspan="2">`
+					if value.GithubBaseInfo.ContributorsTotal >= 100 {
+						contributors += `<a href="` + githubRepoURL + `/graphs/contributors">Total: 99+</a>`
+					} else {
+						contributors += `<a href="` + githubRepoURL + `/graphs/contributors">Total: ` + strconv.Itoa(value.GithubBaseInfo.ContributorsTotal) + `</a>`
+					}
+					contributors += `</td>`
+					contributors += `</tr>`
+
+					contributors += `</table>`
+				}
+				// contributors end
+			}
+		}
+		markdownTableList = append(
+			markdownTableList,
+			markdownTable{
+				Name:          name,
+				Registry:      assembleRegistry(value),
+				Version:       version,
+				VersionAhead:  versionAhead,
+				Description:   value.Description,
+				Keywords:      keywords,
+				LicenseName:   licenseName,
+				PublishTime:   publishTime,
+				Releases:      releases,
+				Dependencies:  dependencies,
+				GithubStars:   githubStars,
+				OhpmLikes:     ohpmLikes,
+				OhpmDownloads: ohpmDownloads,
+				Points:        points,
+				PointsDetail:  pointsDetail,
+				Health:        health,
+				Popularity:    popularity,
+				Issues:        issues,
+				PullRequests:  pullRequests,
+				Contributors:  contributors,
+			},
+		)
+	}
+
+	registryHeader, registryDivider := "", ""
+	if showRegistry {
+		registryHeader, registryDivider = " <sub>Registry</sub> |", ":--------------------:|"
+	}
+	markdown := ""
+	markdown += "<sub>Sort by " + sortField + " | Total " + strconv.Itoa(len(markdownTableList)) + "</sub> \n\n" +
+		"| <sub>Package</sub> |" + registryHeader + " <sub>Stars/Likes</sub> | <sub>Downloads/Popularity / Points</sub> | <sub>Health</sub> | <sub>Issues / Pull_requests</sub> | <sub>Contributors</sub> | \n" +
+		"|--------------------|" + registryDivider + "------------------------|------------------------------|:-----------------:|-----------------------------------|:-----------------------:| \n"
+	for _, value := range markdownTableList {
+		registry := ""
+		if showRegistry {
+			registry = " | " + value.Registry
+		}
+		markdown += "" +
+			"| " + value.Name + " <sup><strong>" + value.Version + "</strong></sup>" + value.VersionAhead + " <br/> <sub>" + FormatString(value.Description) + "</sub>" + formatOptionalLine(value.Keywords) + " <br/> <sub>" + value.LicenseName + "</sub> <br/> <sub>" + value.PublishTime + "</sub>" + formatOptionalLine(value.Releases) + formatOptionalLine(value.Dependencies) +
+			registry +
+			" | " + value.GithubStars + " <br/> " + value.OhpmLikes +
+			" | " + value.OhpmDownloads + " <br/> " + value.Popularity + " <br/> " + value.Points + value.PointsDetail +
+			" | " + value.Health +
+			" | " + value.Issues + " <br/> " + value.PullRequests +
+			" | " + value.Contributors +
+			" | \n"
+	}
+	return markdown
+}
+
+// 组装 registry 列内容：链接到 registry 网页，默认 registry 显示域名
+//
+// 参数:
+//   - [packageInfo] package 信息
+func assembleRegistry(packageInfo ohpm.PackageInfo) string {
+	name := packageInfo.Registry
+	if name == "" {
+		if registryURL, err := url.Parse(packageInfo.RegistryURL()); err == nil {
+			name = registryURL.Host
+		}
+	}
+	return "<sub>[" + FormatString(name) + "](" + packageInfo.RegistryURL() + ")</sub>"
+}
+
+// 组装 points 评分明细（可展开的 `<details>`，每项一行：得分/满分，未得满分时附原因）
+//
+// 参数:
+//   - [items] 评分项
+//
+// 返回值:
+//   - 单行 HTML 内容（无评分项时为空字符）
+func assemblePointsDetail(items []ohpm.PackagePointItem) string {
+	if len(items) == 0 {
+		return ""
+	}
+	lines := []string{}
+	for _, item := range items {
+		line := "✅ "
+		if item.Score < item.Point {
+			line = "⚠️ "
+		}
+		line += FormatString(item.Name) + " " + strconv.Itoa(item.Score) + "/" + strconv.Itoa(item.Point)
+		if item.Score < item.Point && item.Message != "" {
+			line += " · " + FormatString(item.Message)
+		}
+		lines = append(lines, line)
+	}
+	return " <details><summary><sub>Breakdown</sub></summary><sub>" + strings.Join(lines, "<br/>") + "</sub></details>"
+}
+
+// 按 points 占 maxPoints 的比例获取徽章颜色
+//
+// 参数:
+//   - [points]    得分
+//   - [maxPoints] 满分
+//
+// 返回值:
+//   - 十六进制颜色（不含 #）
+func PointsColor(points int, maxPoints int) string {
+	pointsValue := float64(points)
+	maxPointsValue := float64(maxPoints)
+	color := "4AC51C"
+	if pointsValue < maxPointsValue {
+		color = "95C30D"
+	}
+	if pointsValue < maxPointsValue*0.5 {
+		color = "9FA226"
+	}
+	if pointsValue < maxPointsValue*0.2 {
+		color = "D6AE22"
+	}
+	if pointsValue < maxPointsValue*0.1 {
+		color = "D66049"
+	}
+	return color
+}
+
+// 组装版本历史内容（每个 package 一个可折叠的 <details> 块）
+//
+// 参数:
+//   - [packageInfoList]  信息列表
+//
+// 返回值:
+//   - markdown 版本历史内容
+func AssembleMarkdownChangelog(packageInfoList []ohpm.PackageInfo) string {
+	markdown := ""
+	for _, value := range packageInfoList {
+		if value.Code == 0 || len(value.Versions) == 0 {
+			continue
+		}
+		markdown += "<details><summary><strong>" + value.Name + "</strong> v" + value.Version + "</summary>\n\n"
+		for _, version := range value.Versions {
+			markdown += "- v" + version.Version + " <sub>" + timestampFormat(version.PublishTime) + "</sub>"
+			if version.Deprecated {
+				markdown += " ⚠️ deprecated"
+			}
+			markdown += "\n"
+		}
+		markdown += "\n</details>\n"
+	}
+	return markdown
+}
+
+// 组装 package 之间的依赖关系图（Mermaid），仅包含当前列表内互相依赖的 package
+//
+// 参数:
+//   - [packageInfoList]  信息列表
+//
+// 返回值:
+//   - markdown 依赖关系图内容
+func AssembleMarkdownDependencyGraph(packageInfoList []ohpm.PackageInfo) string {
+	// package 名称 -> Mermaid 节点 ID（按列表顺序分配，保证输出确定）
+	nodeIds := map[string]string{}
+	for _, value := range packageInfoList {
+		if value.Code == 1 {
+			nodeIds[value.Name] = "p" + strconv.Itoa(len(nodeIds))
+		}
+	}
+	node := func(name string) string {
+		return nodeIds[name] + `["` + strings.ReplaceAll(name, `"`, "#quot;") + `"]`
+	}
+
+	edges := []string{}
+	for _, value := range packageInfoList {
+		if value.Code == 0 {
+			continue
+		}
+		dependencyNames := make([]string, 0, len(value.Dependencies))
+		for dependencyName := range value.Dependencies {
+			dependencyNames = append(dependencyNames, dependencyName)
+		}
+		sort.Strings(dependencyNames)
+		for _, dependencyName := range dependencyNames {
+			if _, ok := nodeIds[dependencyName]; ok && dependencyName != value.Name {
+				edges = append(edges, "  "+node(value.Name)+" --> "+node(dependencyName))
+			}
+		}
+	}
+	if len(edges) == 0 {
+		return "\n<sub>No dependencies between packages</sub>\n"
+	}
+	return "\n```mermaid\ngraph LR\n" + strings.Join(edges, "\n") + "\n```\n"
+}
+
+// 组装 Publisher 汇总内容（每个 publisher 一行）
+//
+// 下载量、点赞数等由已抓取的 package 信息按 PublisherId 汇总。
+//
+// 参数:
+//   - [publisherProfiles] Publisher 列表
+//   - [packageInfoList]   信息列表
+//
+// 返回值:
+//   - markdown Publisher 汇总内容（无 publisher 时为空）
+func AssembleMarkdownPublisherSummary(publisherProfiles []ohpm.PublisherProfile, packageInfoList []ohpm.PackageInfo) string {
+	if len(publisherProfiles) == 0 {
+		return ""
+	}
+	markdown := " \n" +
+		"| <sub>Publisher</sub> | <sub>Packages</sub> | <sub>Downloads</sub> | <sub>Likes</sub> | \n" +
+		"|----------------------|:-------------------:|:--------------------:|:----------------:| \n"
+	for _, profile := range publisherProfiles {
+		packages, downloads, likes := 0, 0, 0
+		for _, value := range packageInfoList {
+			if value.Code == 1 && value.PublisherId == profile.Id && value.Registry == profile.Registry {
+				packages++
+				downloads += value.Downloads
+				likes += value.Likes
+			}
+		}
+		packageTotal := profile.PackageTotal
+		if packageTotal < packages {
+			packageTotal = packages
+		}
+		name := profile.Name
+		if name == "" {
+			name = profile.Id
+		}
+		publisher := "[" + FormatString(name) + "](" + profile.DetailURL() + ")"
+		if profile.Avatar != "" {
+			publisher = `<img width="20px" src="` + html.EscapeString(profile.Avatar) + `" /> ` + publisher
+		}
+		markdown += "| " + publisher +
+			" | " + strconv.Itoa(packageTotal) +
+			" | " + FormatNumber(downloads) +
+			" | " + FormatNumber(likes) +
+			" | \n"
+	}
+	return markdown
+}
+
+// 由于直接获取 GithubContributorsInfo.AvatarUrl 有可能会是私有头像地址，
+// github.com 暂时固定头像地址；GitHub Enterprise Server 使用接口返回的头像地址。
+//
+// 参数:
+//   - [packageInfo] package 信息
+//   - [contributor] 贡献者
+func getGithubAvatarUrl(packageInfo ohpm.PackageInfo, contributor ohpm.GithubContributorsInfo) string {
+	if !packageInfo.IsGithubCom() && contributor.AvatarUrl != "" {
+		return contributor.AvatarUrl
+	}
+	return "https://avatars.githubusercontent.com/u/" + strconv.Itoa(contributor.Id) + "?v=4"
+}
+
+// 格式化字符串（防止 markdown 格式错乱，并转义 HTML 标签以免第三方内容注入）
+//
+// 参数:
+//   - [v] 需要格式化的字符
+//
+// 返回值:
+//   - 格式化后的字符
+func FormatString(v string) string {
+	value := v
+	value = strings.ReplaceAll(value, "\n", " ")
+	value = strings.ReplaceAll(value, "|", "丨")
+	value = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(value)
+	return value
+}
+
+// 格式化可选的表格行（为空时不输出）
+//
+// 参数:
+//   - [v] 行内容
+//
+// 返回值:
+//   - ` <br/> <sub>v</sub>` 或空字符
+func formatOptionalLine(v string) string {
+	if v == "" {
+		return ""
+	}
+	return " <br/> <sub>" + v + "</sub>"
+}
+
+// 格式化下载数量（便于展示）
+//
+// 参数:
+//   - [num] 需要格式化的数量
+//
+// 返回值:
+//   - 格式化后的数量字符
+func FormatNumber(num int) string {
+	var formatted, suffix string
+	if num >= 1000000 {
+		formatted = fmt.Sprintf("%.2f", float64(num)/1000000)
+		suffix = "M"
+	} else if num >= 1000 {
+		formatted = fmt.Sprintf("%.2f", float64(num)/1000)
+		suffix = "k"
+	} else {
+		return strconv.Itoa(num)
+	}
+
+	// 去掉多余的 0 和小数点
+	formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+	return formatted + suffix
+}
+
+func timestampFormat(millisecondTimestamp int) string {
+	timestamp := int64(millisecondTimestamp)
+	seconds := timestamp / 1000
+	nanoseconds := (timestamp % 1000) * int64(time.Millisecond)
+	t := time.Unix(seconds, nanoseconds)
+	return t.Format(time.RFC3339)
+}
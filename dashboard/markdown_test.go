@@ -0,0 +1,287 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestReleaseStats(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := int(24 * time.Hour / time.Millisecond)
+	nowMs := int(now.UnixMilli())
+	info := ohpm.PackageInfo{
+		PublishTime: nowMs - 10*day,
+		Versions: []ohpm.PackageVersion{
+			{Version: "1.2.0", PublishTime: nowMs - 10*day},
+			{Version: "1.1.0", PublishTime: nowMs - 80*day},
+			{Version: "1.0.0", PublishTime: nowMs - 200*day},
+		},
+	}
+
+	if got := countReleasesSince(info.Versions, now.AddDate(0, 0, -90)); got != 2 {
+		t.Errorf("countReleasesSince = %d, want 2", got)
+	}
+	if got := firstPublishTime(info); got != nowMs-200*day {
+		t.Errorf("firstPublishTime = %d, want %d", got, nowMs-200*day)
+	}
+	if got := daysSince(info.PublishTime, now); got != 10 {
+		t.Errorf("daysSince = %d, want 10", got)
+	}
+	// 无版本历史 -> 回退为最新发布时间
+	if got := firstPublishTime(ohpm.PackageInfo{PublishTime: 123}); got != 123 {
+		t.Errorf("firstPublishTime fallback = %d, want 123", got)
+	}
+	if got := daysSince(0, now); got != 0 {
+		t.Errorf("daysSince(0) = %d, want 0", got)
+	}
+}
+
+func TestAssembleMarkdownTableReleases(t *testing.T) {
+	now := time.Now()
+	info := ohpm.PackageInfo{
+		Code: 1, Name: "@a/b", Version: "1.1.0", PublishTime: int(now.AddDate(0, 0, -10).UnixMilli()),
+		Versions: []ohpm.PackageVersion{
+			{Version: "1.1.0", PublishTime: int(now.AddDate(0, 0, -10).UnixMilli())},
+			{Version: "1.0.0", PublishTime: int(time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC).UnixMilli())},
+		},
+	}
+
+	got := AssembleMarkdownTable([]ohpm.PackageInfo{info}, "name")
+	want := " <br/> <sub><strong>Releases:</strong> 1 in 90d · first 2024-01-02 · last 10d ago</sub>"
+	if !strings.Contains(got, want) {
+		t.Errorf("missing %q in:\n%s", want, got)
+	}
+	// 无法获取信息的 package 不显示
+	if got := AssembleMarkdownTable([]ohpm.PackageInfo{{Code: 0, Name: "@a/c"}}, "name"); strings.Contains(got, "Releases:") {
+		t.Errorf("unexpected release stats:\n%s", got)
+	}
+}
+
+func TestAssembleMarkdownTableDependencies(t *testing.T) {
+	info := ohpm.PackageInfo{Code: 1, Name: "@a/b", Version: "1.0.0", Dependents: 7, Dependencies: map[string]string{"@a/c": "^1.0.0", "@a/d": "^2.0.0"}}
+
+	got := AssembleMarkdownTable([]ohpm.PackageInfo{info}, "name")
+	want := " <br/> <sub><strong>Dependents:</strong> 7 · <strong>Dependencies:</strong> 2</sub>"
+	if !strings.Contains(got, want) {
+		t.Errorf("missing %q in:\n%s", want, got)
+	}
+}
+
+func TestAssembleMarkdownChangelog(t *testing.T) {
+	list := []ohpm.PackageInfo{
+		{Code: 0, Name: "missing"},
+		{Code: 1, Name: "no-history", Version: "1.0.0"},
+		{Code: 1, Name: "@a/b", Version: "1.1.0", Versions: []ohpm.PackageVersion{
+			{Version: "1.1.0", PublishTime: 200},
+			{Version: "1.0.0", PublishTime: 100, Deprecated: true},
+		}},
+	}
+	got := AssembleMarkdownChangelog(list)
+	if strings.Contains(got, "missing") || strings.Contains(got, "no-history") {
+		t.Errorf("unexpected package without history in %q", got)
+	}
+	if strings.Count(got, "<details>") != 1 {
+		t.Errorf("want exactly 1 <details> block, got %q", got)
+	}
+	if !strings.Contains(got, "- v1.0.0") || !strings.Contains(got, "deprecated") {
+		t.Errorf("missing version rows in %q", got)
+	}
+}
+
+func TestAssembleMarkdownDependencyGraph(t *testing.T) {
+	t.Run("only edges between listed packages", func(t *testing.T) {
+		list := []ohpm.PackageInfo{
+			{Code: 1, Name: "@a/core"},
+			{Code: 1, Name: "@a/ui", Dependencies: map[string]string{"@a/core": "^1.0.0", "@ohos/external": "1.0.0"}},
+			{Code: 1, Name: "@a/app", Dependencies: map[string]string{"@a/ui": "^1.0.0", "@a/core": "^1.0.0"}},
+			{Code: 0, Name: "@a/missing"},
+		}
+		got := AssembleMarkdownDependencyGraph(list)
+		want := "\n```mermaid\ngraph LR\n" +
+			"  p1[\"@a/ui\"] --> p0[\"@a/core\"]\n" +
+			"  p2[\"@a/app\"] --> p0[\"@a/core\"]\n" +
+			"  p2[\"@a/app\"] --> p1[\"@a/ui\"]\n" +
+			"```\n"
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("no internal dependencies", func(t *testing.T) {
+		list := []ohpm.PackageInfo{{Code: 1, Name: "@a/core", Dependencies: map[string]string{"@ohos/x": "1.0.0"}}}
+		if got := AssembleMarkdownDependencyGraph(list); strings.Contains(got, "mermaid") {
+			t.Errorf("unexpected mermaid block: %q", got)
+		}
+	})
+}
+
+func TestAssembleMarkdownPublisherSummary(t *testing.T) {
+	profiles := []ohpm.PublisherProfile{
+		{Id: "p1", Name: "Candies", Avatar: "https://example.com/a.png", PackageTotal: 5},
+		{Id: "p2"},
+	}
+	list := []ohpm.PackageInfo{
+		{Code: 1, Name: "a", PublisherId: "p1", Downloads: 1000, Likes: 3},
+		{Code: 1, Name: "b", PublisherId: "p1", Downloads: 200, Likes: 2},
+		{Code: 1, Name: "c", PublisherId: "p2", Downloads: 7, Likes: 1},
+		{Code: 0, Name: "d", PublisherId: "p2"},
+	}
+	got := AssembleMarkdownPublisherSummary(profiles, list)
+	for _, want := range []string{
+		`<img width="20px" src="https://example.com/a.png" /> [Candies](https://ohpm.openharmony.cn/#/cn/publisher/p1) | 5 | 1.2k | 5 |`,
+		// 无 profile 信息时回退为 ID，数量取已抓取的 package 数
+		`[p2](https://ohpm.openharmony.cn/#/cn/publisher/p2) | 1 | 7 | 1 |`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in %q", want, got)
+		}
+	}
+
+	if got := AssembleMarkdownPublisherSummary(nil, list); got != "" {
+		t.Errorf("got %q, want empty without publishers", got)
+	}
+}
+
+func TestAssemblePointsDetail(t *testing.T) {
+	if got := assemblePointsDetail(nil); got != "" {
+		t.Errorf("no items = %q, want empty", got)
+	}
+	got := assemblePointsDetail([]ohpm.PackagePointItem{
+		{Name: "README", Score: 20, Point: 20, Message: "ignored"},
+		{Name: "License <x>", Score: 0, Point: 20, Message: "missing | invalid"},
+	})
+	want := " <details><summary><sub>Breakdown</sub></summary><sub>✅ README 20/20<br/>⚠️ License &lt;x&gt; 0/20 · missing 丨 invalid</sub></details>"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		in   int
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1k"},
+		{1200, "1.2k"},
+		{1234, "1.23k"},
+		{999999, "1000k"},
+		{1000000, "1M"},
+		{2500000, "2.5M"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := FormatNumber(tt.in); got != tt.want {
+				t.Errorf("FormatNumber(%d) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"a\nb", "a b"},
+		{"a|b", "a丨b"},
+		{"line1\nline2|col", "line1 line2丨col"},
+		{"plain", "plain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := FormatString(tt.in); got != tt.want {
+				t.Errorf("FormatString(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatStringEscapesHTML(t *testing.T) {
+	if got, want := FormatString("<img src=x> & co"), "&lt;img src=x&gt; &amp; co"; got != want {
+		t.Errorf("FormatString = %q, want %q", got, want)
+	}
+
+	info := ohpm.PackageInfo{Code: 1, Name: "@a/b", Version: "1.0.0", LicenseName: "<script>MIT</script>"}
+	got := AssembleMarkdownTable([]ohpm.PackageInfo{info}, "name")
+	if strings.Contains(got, "<script>") || !strings.Contains(got, "&lt;script&gt;MIT&lt;/script&gt;") {
+		t.Errorf("license not escaped:\n%s", got)
+	}
+}
+
+func TestAssembleMarkdownTableRegistry(t *testing.T) {
+	list := []ohpm.PackageInfo{
+		{Code: 1, Name: "@a/b", Version: "1.0.0"},
+		{Code: 0, Name: "@corp/utils", Registry: "internal", RegistryWebURL: "https://ohpm.corp.example.com"},
+	}
+
+	// 仅默认 registry 时不显示 registry 列
+	if got := AssembleMarkdownTable(list[:1], "name"); strings.Contains(got, "Registry") {
+		t.Errorf("unexpected registry column:\n%s", got)
+	}
+
+	got := AssembleMarkdownTable(list, "name")
+	for _, want := range []string{
+		"| <sub>Package</sub> | <sub>Registry</sub> | <sub>Stars/Likes</sub> |",
+		"[@a/b](https://ohpm.openharmony.cn/#/cn/detail/@a%2Fb)",
+		" | <sub>[ohpm.openharmony.cn](https://ohpm.openharmony.cn)</sub> | ",
+		"@corp/utils ⁉️",
+		" | <sub>[internal](https://ohpm.corp.example.com)</sub> | ",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestAssembleMarkdownTableGithubEnterprise(t *testing.T) {
+	info := ohpm.PackageInfo{Code: 1, Name: "@corp/app", Version: "1.0.0", GithubUser: "team", GithubRepo: "app", GithubWebURL: "https://github.example.com",
+		GithubContributorsInfo: []ohpm.GithubContributorsInfo{{Login: "a", Id: 1, AvatarUrl: "https://github.example.com/avatars/u/1", HtmlUrl: "https://github.example.com/a", Type: "User"}}}
+	info.GithubBaseInfo.StargazersCount = 1200
+	info.GithubBaseInfo.OpenIssuesCount = 4
+	info.GithubBaseInfo.ContributorsTotal = 1
+
+	got := AssembleMarkdownTable([]ohpm.PackageInfo{info}, "name")
+	for _, want := range []string{
+		"[![GitHub stars](https://img.shields.io/badge/1.2k-_?style=social&logo=github&logoColor=1F2328&label=)](https://github.example.com/team/app)",
+		"[![GitHub issues](https://img.shields.io/badge/4_open-yellow)](https://github.example.com/team/app/issues)",
+		"[Pull requests](https://github.example.com/team/app/pulls)",
+		`<img width="36px" src="https://github.example.com/avatars/u/1" />`,
+		`<a href="https://github.example.com/team/app/graphs/contributors">Total: 1</a>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "img.shields.io/github/") {
+		t.Errorf("unexpected github.com shields badge:\n%s", got)
+	}
+}
+
+func TestAssembleMarkdownTableGithubSkipped(t *testing.T) {
+	// 额度耗尽时 github.com 与 GitHub Enterprise Server 均只显示链接
+	for _, webURL := range []string{"https://github.example.com", ohpm.DefaultGithubWebURL} {
+		t.Run(webURL, func(t *testing.T) {
+			info := ohpm.PackageInfo{Code: 1, Name: "@corp/app", Version: "1.0.0", GithubUser: "team", GithubRepo: "app", GithubWebURL: webURL, GithubSkipped: true}
+
+			got := AssembleMarkdownTable([]ohpm.PackageInfo{info}, "name")
+			for _, want := range []string{
+				"[GitHub](" + webURL + "/team/app)",
+				"[Issues](" + webURL + "/team/app/issues)",
+				"[Pull requests](" + webURL + "/team/app/pulls)",
+				`<a href="` + webURL + `/team/app/graphs/contributors">Contributors</a>`,
+			} {
+				if !strings.Contains(got, want) {
+					t.Errorf("missing %q in:\n%s", want, got)
+				}
+			}
+			if strings.Contains(got, "GitHub stars") || strings.Contains(got, "GitHub issues") {
+				t.Errorf("unexpected github badge without data:\n%s", got)
+			}
+		})
+	}
+}
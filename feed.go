@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/dashboard"
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// 写入 Atom feed 文件
+//
+// 参数:
+//   - [filename]        输出文件，如 "releases.atom"
+//   - [packageInfoList] 信息列表
+func writeAtomFeed(filename string, packageInfoList []ohpm.PackageInfo) error {
+	data, err := dashboard.AssembleAtomFeed("ohpm-dashboard releases", packageInfoList, time.Now())
+	if err != nil {
+		return fmt.Errorf("📰❌ writeAtomFeed: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("📰❌ writeAtomFeed: Error writing a file: %w", err)
+	}
+	slog.Info("📰✅ writeAtomFeed: Success", "file", filename)
+	return nil
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestWriteAtomFeed(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "releases.atom")
+	if err := writeAtomFeed(filename, []ohpm.PackageInfo{{Code: 1, Name: "a", Version: "1.0.0", PublishTime: 1000}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil || !strings.Contains(string(data), "<title>a v1.0.0</title>") {
+		t.Errorf("got %q, err %v", data, err)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// 读取 GitHub Enterprise Server 主机配置
+//
+// 仓库链接按主机域名匹配，github.com 与 GitHub Enterprise Server 的仓库可以同时出现在一个仪表盘中，
+// 各主机使用各自的令牌（github.com 使用 `-githubToken`）。
+// token 中的 `$VAR` / `${VAR}` 从环境变量展开，避免将令牌写入仓库。
+//
+// 配置文件:
+//
+//	[{"webURL": "https://github.example.com", "apiURL": "https://github.example.com/api/v3", "token": "${GHE_TOKEN}"}]
+//
+// 参数:
+//   - [filename] 配置 JSON 文件（[ohpm.GithubHost] 数组），为空时仅 github.com
+//
+// 返回值:
+//   - Github 主机列表（token 已展开环境变量）
+func loadGithubHosts(filename string) ([]ohpm.GithubHost, error) {
+	if filename == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("🐙❌ loadGithubHosts: Error reade a file: %w", err)
+	}
+	hosts := []ohpm.GithubHost{}
+	if err := json.Unmarshal(data, &hosts); err != nil {
+		return nil, fmt.Errorf("🐙❌ loadGithubHosts: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for i := range hosts {
+		host := &hosts[i]
+		webURL, err := url.Parse(host.WebURL)
+		if err != nil || webURL.Host == "" {
+			return nil, fmt.Errorf("🐙❌ loadGithubHosts: invalid webURL %q", host.WebURL)
+		}
+		if seen[webURL.Host] {
+			return nil, fmt.Errorf("🐙❌ loadGithubHosts: duplicate host %q", webURL.Host)
+		}
+		seen[webURL.Host] = true
+		host.WebURL = strings.TrimSuffix(host.WebURL, "/")
+		host.APIURL = strings.TrimSuffix(host.APIURL, "/")
+		host.Token = os.ExpandEnv(host.Token)
+	}
+	return hosts, nil
+}
+
+// 获取 github.com 的令牌
+//
+// 依次取 `-githubToken`、`-githubTokenFile`、环境变量 GITHUB_TOKEN、GH_TOKEN，均为空时匿名访问。
+// 命令行参数会出现在进程列表与 Action 日志中，推荐使用环境变量或令牌文件。
+//
+// 参数:
+//   - [githubToken]     `-githubToken` 的值
+//   - [githubTokenFile] 令牌文件（首尾空白会被去除）
+//
+// 返回值:
+//   - 令牌（匿名访问时为空）
+func resolveGithubToken(githubToken string, githubTokenFile string) (string, error) {
+	if githubToken != "" {
+		return githubToken, nil
+	}
+	if githubTokenFile != "" {
+		data, err := os.ReadFile(githubTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("🐙❌ resolveGithubToken: Error reade a file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	for _, name := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if value := os.Getenv(name); value != "" {
+			return value, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadGithubHosts(t *testing.T) {
+	t.Run("empty filename", func(t *testing.T) {
+		hosts, err := loadGithubHosts("")
+		if err != nil || hosts != nil {
+			t.Errorf("got %v, %v", hosts, err)
+		}
+	})
+
+	t.Run("expands token from environment", func(t *testing.T) {
+		t.Setenv("GHE_TOKEN", "secret")
+		filename := filepath.Join(t.TempDir(), "github-hosts.json")
+		os.WriteFile(filename, []byte(`[{"webURL":"https://github.example.com/","token":"$GHE_TOKEN"}]`), 0644)
+		hosts, err := loadGithubHosts(filename)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(hosts) != 1 || hosts[0].WebURL != "https://github.example.com" || hosts[0].Token != "secret" {
+			t.Errorf("got %+v", hosts)
+		}
+
+		client, err := newClient(clientOptions{githubToken: "public", githubConfig: filename})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client.GithubToken != "public" || len(client.GithubHosts) != 1 {
+			t.Errorf("got %+v", client)
+		}
+	})
+
+	for name, content := range map[string]string{
+		"invalid webURL": `[{"webURL":"github.example.com"}]`,
+		"duplicate host": `[{"webURL":"https://github.example.com"},{"webURL":"https://github.example.com/"}]`,
+		"invalid json":   `{`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "github-hosts.json")
+			os.WriteFile(filename, []byte(content), 0644)
+			if _, err := loadGithubHosts(filename); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestResolveGithubToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	filename := filepath.Join(t.TempDir(), "token")
+	os.WriteFile(filename, []byte("from-file\n"), 0600)
+
+	tests := []struct {
+		name        string
+		flag        string
+		file        string
+		githubToken string
+		ghToken     string
+		want        string
+	}{
+		{"flag first", "from-flag", filename, "from-env", "", "from-flag"},
+		{"file before env", "", filename, "from-env", "", "from-file"},
+		{"GITHUB_TOKEN before GH_TOKEN", "", "", "from-env", "from-gh", "from-env"},
+		{"GH_TOKEN", "", "", "", "from-gh", "from-gh"},
+		{"anonymous", "", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", tt.githubToken)
+			t.Setenv("GH_TOKEN", tt.ghToken)
+			got, err := resolveGithubToken(tt.flag, tt.file)
+			if err != nil || got != tt.want {
+				t.Errorf("got (%q, %v), want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := resolveGithubToken("", filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for missing token file")
+	}
+}
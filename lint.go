@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AmosHuKe/ohpm-dashboard/dashboard"
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// lint 子命令：检查 package 的 ohpm 元数据，输出每个 package 的问题报告
+//
+// 使用:
+//   - `go run . lint -githubToken xxx -publisherList xxx -packageList xxx`
+//   - `go run . lint -format json -publisherList xxx > lint.json`
+//
+// 规则:
+//   - `notFound`           ohpm 上无法获取 package
+//   - `missingDescription` 缺少描述
+//   - `descriptionTooLong` 描述超过 [lintDescriptionMaxLength] 个字符，表格中过长
+//   - `noGithubLink`       homepage、repository 均无法解析为 Github 仓库地址
+//   - `githubRepoNotFound` Github 仓库不存在（404）
+//   - `githubRepoArchived` Github 仓库已归档
+//   - `license`            license 问题，见 dashboard/license.go
+//
+// 参数:
+//   - [githubToken] 拥有 repo 权限的 Github 令牌
+//   - [format]      报告格式 可选：text(default) | json
+//   - 其余参数同 update 流程：publisherList、packageList、searchQuery、searchMax、sortField、sortMode、licenseAllowlist
+//
+// 返回值:
+//   - 进程退出码（存在问题时为 [exitFindings]）
+func runLint(args []string) int {
+	var format string
+	var options clientOptions
+	var config dashboard.Config
+	flagSet := newCommandFlagSet("lint", lintSummary)
+	registerFetchFlags(flagSet, &options, &config)
+	flagSet.StringVar(&format, "format", "text", "text | json")
+	if code, ok := parseCommandFlags(flagSet, args); !ok {
+		return code
+	}
+	if format != "text" && format != "json" {
+		slog.Error("🧹❌ lint: unknown format", "format", format)
+		return exitError
+	}
+
+	report := runReport{command: "lint", start: time.Now()}
+	defer func() { finishRun(report) }()
+	client, err := newClient(options)
+	if err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+
+	// 日志输出到 stderr，stdout 只保留报告（便于 `> lint.json`）
+	packageInfoList, err := dashboard.Fetch(context.Background(), client, config)
+	if err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	report.packages = packageInfoList
+
+	reports := lintPackages(packageInfoList)
+	if err := writeLintReport(os.Stdout, reports, format); err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	for _, report := range reports {
+		if len(report.Findings) > 0 {
+			return exitFindings
+		}
+	}
+	return exitOK
+}
+
+// 描述的最大长度（字符数），超过时表格中会明显换行
+const lintDescriptionMaxLength = 150
+
+// 单条 lint 问题
+type LintFinding struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// 单个 package 的 lint 报告
+type LintReport struct {
+	Package  string        `json:"package"`
+	Findings []LintFinding `json:"findings"`
+}
+
+// 检查单个 package 的元数据
+//
+// 参数:
+//   - [packageInfo] package 信息（需先 [dashboard.ApplyLicenseChecks]）
+//
+// 返回值:
+//   - [LintFinding] 列表
+func lintPackage(packageInfo ohpm.PackageInfo) []LintFinding {
+	findings := []LintFinding{}
+	if packageInfo.Code == 0 {
+		return append(findings, LintFinding{Rule: "notFound", Message: "package not found on ohpm"})
+	}
+
+	description := strings.TrimSpace(packageInfo.Description)
+	if description == "" {
+		findings = append(findings, LintFinding{Rule: "missingDescription", Message: "no description"})
+	} else if length := utf8.RuneCountInString(description); length > lintDescriptionMaxLength {
+		findings = append(findings, LintFinding{Rule: "descriptionTooLong", Message: "description has " + strconv.Itoa(length) + " characters, max " + strconv.Itoa(lintDescriptionMaxLength)})
+	}
+
+	if packageInfo.GithubUser == "" || packageInfo.GithubRepo == "" {
+		findings = append(findings, LintFinding{Rule: "noGithubLink", Message: fmt.Sprintf("no GitHub repository in homepage %q or repository %q", packageInfo.Homepage, packageInfo.Repository)})
+	} else {
+		githubURL := packageInfo.GithubRepoURL()
+		if packageInfo.GithubBaseInfo.NotFound {
+			findings = append(findings, LintFinding{Rule: "githubRepoNotFound", Message: githubURL + " not found"})
+		}
+		if packageInfo.GithubBaseInfo.Archived {
+			findings = append(findings, LintFinding{Rule: "githubRepoArchived", Message: githubURL + " is archived"})
+		}
+	}
+
+	for _, issue := range packageInfo.LicenseIssues {
+		findings = append(findings, LintFinding{Rule: "license", Message: issue.Message})
+	}
+	return findings
+}
+
+// 检查所有 package 的元数据
+//
+// 参数:
+//   - [packageInfoList] 信息列表
+//
+// 返回值:
+//   - 每个 package 一条 [LintReport]（按 [packageInfoList] 顺序）
+func lintPackages(packageInfoList []ohpm.PackageInfo) []LintReport {
+	reports := []LintReport{}
+	for _, value := range packageInfoList {
+		reports = append(reports, LintReport{Package: value.Name, Findings: lintPackage(value)})
+	}
+	return reports
+}
+
+// 输出 lint 报告
+//
+// 参数:
+//   - [w]       输出目标
+//   - [reports] lint 报告
+//   - [format]  报告格式 可选：text | json
+func writeLintReport(w io.Writer, reports []LintReport, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			return fmt.Errorf("🧹❌ writeLintReport: %w", err)
+		}
+		return nil
+	}
+
+	total := 0
+	out := strings.Builder{}
+	for _, report := range reports {
+		if len(report.Findings) == 0 {
+			out.WriteString("✅ " + report.Package + "\n")
+			continue
+		}
+		out.WriteString("⚠️ " + report.Package + "\n")
+		for _, finding := range report.Findings {
+			out.WriteString("   - " + finding.Rule + ": " + finding.Message + "\n")
+			total++
+		}
+	}
+	out.WriteString("🧹 lint: " + strconv.Itoa(len(reports)) + " package(s), " + strconv.Itoa(total) + " finding(s)\n")
+	if _, err := io.WriteString(w, out.String()); err != nil {
+		return fmt.Errorf("🧹❌ writeLintReport: %w", err)
+	}
+	return nil
+}
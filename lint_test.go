@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AmosHuKe/ohpm-dashboard/dashboard"
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestLintPackage(t *testing.T) {
+	rules := func(findings []LintFinding) []string {
+		result := []string{}
+		for _, finding := range findings {
+			result = append(result, finding.Rule)
+		}
+		return result
+	}
+	healthy := ohpm.PackageInfo{Code: 1, Name: "@a/b", Description: "desc", GithubUser: "u", GithubRepo: "r"}
+
+	tests := []struct {
+		name string
+		info func() ohpm.PackageInfo
+		want []string
+	}{
+		{"healthy", func() ohpm.PackageInfo { return healthy }, []string{}},
+		{"not found", func() ohpm.PackageInfo { return ohpm.PackageInfo{Code: 0, Name: "x"} }, []string{"notFound"}},
+		{"missing description", func() ohpm.PackageInfo {
+			info := healthy
+			info.Description = "  "
+			return info
+		}, []string{"missingDescription"}},
+		{"description too long", func() ohpm.PackageInfo {
+			info := healthy
+			info.Description = strings.Repeat("描", lintDescriptionMaxLength+1)
+			return info
+		}, []string{"descriptionTooLong"}},
+		{"no github link", func() ohpm.PackageInfo {
+			info := healthy
+			info.GithubUser, info.GithubRepo = "", ""
+			return info
+		}, []string{"noGithubLink"}},
+		{"github repo not found and archived", func() ohpm.PackageInfo {
+			info := healthy
+			info.GithubBaseInfo.NotFound = true
+			info.GithubBaseInfo.Archived = true
+			return info
+		}, []string{"githubRepoNotFound", "githubRepoArchived"}},
+		{"license issues", func() ohpm.PackageInfo {
+			info := healthy
+			info.LicenseIssues = []ohpm.LicenseIssue{{Kind: dashboard.LicenseIssueMissing, Message: "no license on ohpm"}}
+			return info
+		}, []string{"license"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules(lintPackage(tt.info())); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("description at max length is fine", func(t *testing.T) {
+		info := healthy
+		info.Description = strings.Repeat("a", lintDescriptionMaxLength)
+		if got := lintPackage(info); len(got) != 0 {
+			t.Errorf("got %v", got)
+		}
+	})
+}
+
+func TestWriteLintReport(t *testing.T) {
+	reports := lintPackages([]ohpm.PackageInfo{
+		{Code: 1, Name: "@a/ok", Description: "desc", GithubUser: "u", GithubRepo: "r"},
+		{Code: 0, Name: "@a/gone"},
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := writeLintReport(&buf, reports, "text"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "✅ @a/ok\n" +
+			"⚠️ @a/gone\n" +
+			"   - notFound: package not found on ohpm\n" +
+			"🧹 lint: 2 package(s), 1 finding(s)\n"
+		if buf.String() != want {
+			t.Errorf("got %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := writeLintReport(&buf, reports, "json"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got []LintReport
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+		if !reflect.DeepEqual(got, reports) {
+			t.Errorf("got %+v, want %+v", got, reports)
+		}
+		// 无问题的 package 输出空数组而非 null
+		if !strings.Contains(buf.String(), `"findings": []`) {
+			t.Errorf("expected empty findings array:\n%s", buf.String())
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// 日志级别，由 `-log-level` 设置，所有 handler 共享
+var logLevel = new(slog.LevelVar)
+
+// 日志写入当前的 os.Stderr（而非创建 handler 时的 os.Stderr），
+// 以便经过 [redactStdio] 隐藏密钥
+type stderrWriter struct{}
+
+func (stderrWriter) Write(p []byte) (int, error) {
+	return os.Stderr.Write(p)
+}
+
+// 创建日志记录器（ERROR 日志同时记录到 [defaultRunErrors]）
+//
+// 参数:
+//   - [w]      输出
+//   - [format] text | json
+func newLogger(w io.Writer, format string) (*slog.Logger, error) {
+	options := &slog.HandlerOptions{Level: logLevel}
+	switch format {
+	case "text":
+		return slog.New(errorHandler{slog.NewTextHandler(w, options)}), nil
+	case "json":
+		return slog.New(errorHandler{slog.NewJSONHandler(w, options)}), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q, want text | json", format)
+}
+
+// `-log-level` 参数：debug | info | warn | error
+type logLevelFlag struct{}
+
+func (logLevelFlag) String() string {
+	return logLevel.Level().String()
+}
+
+func (logLevelFlag) Set(value string) error {
+	return logLevel.UnmarshalText([]byte(value))
+}
+
+// `-log-format` 参数：text | json，设置后替换默认日志记录器
+type logFormatFlag struct {
+	value string
+}
+
+func (f *logFormatFlag) String() string {
+	if f.value == "" {
+		return "text"
+	}
+	return f.value
+}
+
+func (f *logFormatFlag) Set(value string) error {
+	logger, err := newLogger(stderrWriter{}, value)
+	if err != nil {
+		return err
+	}
+	f.value = value
+	slog.SetDefault(logger)
+	return nil
+}
+
+// 一次运行的结果，用于运行汇总与 GitHub Actions 输出（见 actions.go）
+type runReport struct {
+	command  string
+	start    time.Time
+	packages []ohpm.PackageInfo
+	file     string // 更新的 Markdown 文件（仅 update）
+	changed  bool   // Markdown 文件内容是否变化
+	added    int    // 新增行数
+	removed  int    // 删除行数
+}
+
+// 未找到的 package 与跳过 Github 信息的 package 数量
+func (r runReport) counts() (int, int) {
+	notFound, githubSkipped := 0, 0
+	for _, value := range r.packages {
+		if value.Code == 0 {
+			notFound++
+		}
+		if value.GithubSkipped {
+			githubSkipped++
+		}
+	}
+	return notFound, githubSkipped
+}
+
+// 运行汇总：package 数量、未找到、跳过 Github 信息、失败、HTTP 请求与失败次数、耗时
+//
+// 参数:
+//   - [report] 运行结果
+func logRunSummary(report runReport) {
+	notFound, githubSkipped := report.counts()
+	requests, requestErrors := 0, 0
+	for _, value := range defaultHTTPMetrics.snapshot() {
+		requests += value.Requests
+		requestErrors += value.Errors
+	}
+	failures := len(defaultRunErrors.snapshot())
+	level := slog.LevelInfo
+	if notFound > 0 || githubSkipped > 0 || failures > 0 {
+		level = slog.LevelWarn
+	}
+	attrs := []any{
+		"command", report.command,
+		"packages", len(report.packages),
+		"notFound", notFound,
+		"githubSkipped", githubSkipped,
+		"failures", failures,
+		"requests", requests,
+		"requestErrors", requestErrors,
+		"duration", time.Since(report.start).Round(time.Millisecond),
+	}
+	if report.file != "" {
+		attrs = append(attrs, "file", report.file, "changed", report.changed, "added", report.added, "removed", report.removed)
+	}
+	slog.Log(context.Background(), level, "📊 Run summary", attrs...)
+}
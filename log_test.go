@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestNewLogger(t *testing.T) {
+	defer logLevel.Set(slog.LevelInfo)
+
+	out := bytes.Buffer{}
+	logger, err := newLogger(&out, "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger.Debug("hidden")
+	logLevel.Set(slog.LevelDebug)
+	logger.Debug("🌏 HTTP request", "status", 200)
+
+	var entry map[string]any
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("not a single json line: %q", out.String())
+	}
+	if entry["level"] != "DEBUG" || entry["msg"] != "🌏 HTTP request" || entry["status"] != float64(200) {
+		t.Errorf("got %v", entry)
+	}
+
+	if _, err := newLogger(&out, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestLogFlags(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+	defer logLevel.Set(slog.LevelInfo)
+
+	flagSet := newCommandFlagSet("fetch", fetchSummary)
+	if err := flagSet.Parse([]string{"-log-level", "warn", "-log-format", "json"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if logLevel.Level() != slog.LevelWarn {
+		t.Errorf("level = %v", logLevel.Level())
+	}
+	if handler, ok := slog.Default().Handler().(errorHandler); !ok {
+		t.Errorf("handler = %T", slog.Default().Handler())
+	} else if _, ok := handler.Handler.(*slog.JSONHandler); !ok {
+		t.Errorf("handler = %T", slog.Default().Handler())
+	}
+
+	for _, args := range [][]string{{"-log-level", "verbose"}, {"-log-format", "xml"}} {
+		flagSet := newCommandFlagSet("fetch", fetchSummary)
+		flagSet.Init("fetch", flag.ContinueOnError)
+		flagSet.SetOutput(&bytes.Buffer{})
+		if err := flagSet.Parse(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestLogRunSummary(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+	out := bytes.Buffer{}
+	logger, _ := newLogger(&out, "json")
+	slog.SetDefault(logger)
+
+	logRunSummary(runReport{command: "update", start: time.Now(), packages: []ohpm.PackageInfo{{Code: 1}, {Code: 0}, {Code: 1, GithubSkipped: true}}})
+
+	var entry map[string]any
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("not a single json line: %q", out.String())
+	}
+	if entry["level"] != "WARN" || entry["command"] != "update" || entry["packages"] != float64(3) || entry["notFound"] != float64(1) || entry["githubSkipped"] != float64(1) {
+		t.Errorf("got %v", entry)
+	}
+	if !strings.Contains(out.String(), `"requests":`) {
+		t.Errorf("missing requests in %q", out.String())
+	}
+}
@@ -0,0 +1,60 @@
+// Package mdinject 替换 Markdown 文件中的特定占位内容
+//
+// 占位格式：`<!-- md:[name] begin --><!-- md:[name] end -->`，
+// begin 与 end 之间的内容会被整体替换，占位本身保留，便于重复更新。
+package mdinject
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// 获取占位的 begin / end 标记
+//
+// 参数:
+//   - [name] 占位名称，如 "OHPMDashboard-total"
+func Markers(name string) (string, string) {
+	return "<!-- md:" + name + " begin -->", "<!-- md:" + name + " end -->"
+}
+
+// 替换 Markdown 内容中的特定占位内容
+//
+// 识别：<!-- md:[name] begin --><!-- md:[name] end -->，不存在该占位时原样返回
+//
+// 参数:
+//   - [md]      Markdown 内容
+//   - [name]    占位名称，如 "OHPMDashboard-total"
+//   - [content] 替换内容（位于 begin 与 end 之间）
+//
+// 返回值:
+//   - 替换后的 Markdown 内容
+func Replace(md []byte, name string, content string) []byte {
+	begin, end := Markers(name)
+	newMdText := bytes.NewBuffer(nil)
+	newMdText.WriteString(begin)
+	newMdText.WriteString(content)
+	newMdText.WriteString(end)
+
+	reg := regexp.MustCompile(regexp.QuoteMeta(begin) + "(?s)(.*?)" + regexp.QuoteMeta(end))
+	// 使用 ReplaceAllFunc 避免 content 中的 `$` 被当作分组引用展开
+	return reg.ReplaceAllFunc(md, func([]byte) []byte { return newMdText.Bytes() })
+}
+
+// 替换 Markdown 文件中的特定占位内容，文件中不存在该占位时不做修改
+//
+// 参数:
+//   - [filename] 更新的文件
+//   - [name]     占位名称，如 "OHPMDashboard-total"
+//   - [content]  替换内容（位于 begin 与 end 之间）
+func UpdateFile(filename string, name string, content string) error {
+	md, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("Error reade a file: %w", err)
+	}
+	if err := os.WriteFile(filename, Replace(md, name, content), 0644); err != nil {
+		return fmt.Errorf("Error writing a file: %w", err)
+	}
+	return nil
+}
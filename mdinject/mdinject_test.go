@@ -0,0 +1,49 @@
+package mdinject
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplace(t *testing.T) {
+	md := "a <!-- md:OHPMDashboard-total begin -->1<!-- md:OHPMDashboard-total end --> b\n" +
+		"<!-- md:OHPMDashboard begin -->old\nlines<!-- md:OHPMDashboard end -->\n"
+
+	got := Replace([]byte(md), "OHPMDashboard", "new")
+	want := "a <!-- md:OHPMDashboard-total begin -->1<!-- md:OHPMDashboard-total end --> b\n" +
+		"<!-- md:OHPMDashboard begin -->new<!-- md:OHPMDashboard end -->\n"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// `$` 不作为分组引用展开
+	got = Replace([]byte(md), "OHPMDashboard-total", "$1 42")
+	if want := "a <!-- md:OHPMDashboard-total begin -->$1 42<!-- md:OHPMDashboard-total end --> b\n"; string(got[:len(want)]) != want {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestUpdateFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "README.md")
+	md := "<!-- md:OHPMDashboard begin -->old<!-- md:OHPMDashboard end -->\n"
+	if err := os.WriteFile(filename, []byte(md), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 不存在的占位不做修改
+	if err := UpdateFile(filename, "OHPMDashboard-changelog", "x"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := UpdateFile(filename, "OHPMDashboard", "new"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, _ := os.ReadFile(filename)
+	if want := "<!-- md:OHPMDashboard begin -->new<!-- md:OHPMDashboard end -->\n"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if err := UpdateFile(filepath.Join(t.TempDir(), "missing.md"), "OHPMDashboard", "x"); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
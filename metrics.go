@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// Prometheus 指标（text exposition format）
+//
+// package 指标:
+//   - `ohpm_package_found`                          package 是否存在（1/0）
+//   - `ohpm_package_downloads`                      下载量
+//   - `ohpm_package_likes`                          点赞数
+//   - `ohpm_package_points` / `ohpm_package_max_points`  得分 / 满分
+//   - `ohpm_package_popularity`                     流行度
+//   - `ohpm_package_dependents`                     被依赖数量
+//   - `ohpm_package_health_score`                   健康度，见 dashboard/health.go
+//   - `github_repo_stars`                           Github stars
+//
+// HTTP 指标（按 host）:
+//   - `ohpm_dashboard_http_requests_total`          请求次数（含重试）
+//   - `ohpm_dashboard_http_request_errors_total`    失败次数（传输错误、可重试状态码）
+//   - `ohpm_dashboard_http_request_duration_seconds` 请求耗时（summary：_sum / _count）
+
+// 单个 host 的 HTTP 指标
+type httpHostMetrics struct {
+	Requests        int
+	Errors          int
+	DurationSeconds float64
+}
+
+// httpMetrics 记录 ohpm 客户端每次 HTTP 尝试的耗时与失败次数（并发安全）。
+type httpMetrics struct {
+	mutex sync.Mutex
+	hosts map[string]httpHostMetrics
+}
+
+// 进程级 HTTP 指标，由 [newClient] 创建的客户端通过 OnRequest 记录
+var defaultHTTPMetrics = &httpMetrics{hosts: map[string]httpHostMetrics{}}
+
+// 记录一次 HTTP 尝试
+//
+// 参数:
+//   - [host]     请求 host
+//   - [duration] 耗时
+//   - [failed]   是否失败
+func (m *httpMetrics) observe(host string, duration time.Duration, failed bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	value := m.hosts[host]
+	value.Requests++
+	value.DurationSeconds += duration.Seconds()
+	if failed {
+		value.Errors++
+	}
+	m.hosts[host] = value
+}
+
+// 获取当前指标副本
+func (m *httpMetrics) snapshot() map[string]httpHostMetrics {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	hosts := make(map[string]httpHostMetrics, len(m.hosts))
+	for host, value := range m.hosts {
+		hosts[host] = value
+	}
+	return hosts
+}
+
+// 组装 Prometheus 指标内容
+//
+// 参数:
+//   - [packageInfoList] 信息列表（同名 package 只输出首个）
+//   - [hosts]           HTTP 指标（可为 nil）
+//
+// 返回值:
+//   - text exposition format 内容
+func assemblePrometheusMetrics(packageInfoList []ohpm.PackageInfo, hosts map[string]httpHostMetrics) string {
+	seen := map[string]bool{}
+	packages := []ohpm.PackageInfo{}
+	for _, value := range packageInfoList {
+		if !seen[value.Name] {
+			seen[value.Name] = true
+			packages = append(packages, value)
+		}
+	}
+	sort.SliceStable(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+
+	out := strings.Builder{}
+	gauge := func(name string, help string, value func(ohpm.PackageInfo) (int, bool), extraLabels func(ohpm.PackageInfo) string) {
+		out.WriteString("# HELP " + name + " " + help + "\n")
+		out.WriteString("# TYPE " + name + " gauge\n")
+		for _, packageInfo := range packages {
+			v, ok := value(packageInfo)
+			if !ok {
+				continue
+			}
+			labels := `package="` + escapeLabelValue(packageInfo.Name) + `",publisher="` + escapeLabelValue(packageInfo.PublisherId) + `"`
+			if extraLabels != nil {
+				labels += extraLabels(packageInfo)
+			}
+			out.WriteString(name + "{" + labels + "} " + strconv.Itoa(v) + "\n")
+		}
+	}
+	found := func(get func(ohpm.PackageInfo) int) func(ohpm.PackageInfo) (int, bool) {
+		return func(packageInfo ohpm.PackageInfo) (int, bool) {
+			return get(packageInfo), packageInfo.Code == 1
+		}
+	}
+
+	gauge("ohpm_package_found", "Whether the package exists on ohpm (1) or not (0).", func(packageInfo ohpm.PackageInfo) (int, bool) {
+		return packageInfo.Code, true
+	}, nil)
+	gauge("ohpm_package_downloads", "Total ohpm downloads.", found(func(p ohpm.PackageInfo) int { return p.Downloads }), nil)
+	gauge("ohpm_package_likes", "Total ohpm likes.", found(func(p ohpm.PackageInfo) int { return p.Likes }), nil)
+	gauge("ohpm_package_points", "ohpm points.", found(func(p ohpm.PackageInfo) int { return p.Points }), nil)
+	gauge("ohpm_package_max_points", "ohpm max points.", found(func(p ohpm.PackageInfo) int { return p.MaxPoints }), nil)
+	gauge("ohpm_package_popularity", "ohpm popularity.", found(func(p ohpm.PackageInfo) int { return p.Popularity }), nil)
+	gauge("ohpm_package_dependents", "Number of ohpm packages depending on the package.", found(func(p ohpm.PackageInfo) int { return p.Dependents }), nil)
+	gauge("ohpm_package_health_score", "Package health score (0-100).", found(func(p ohpm.PackageInfo) int { return p.HealthScore }), nil)
+	gauge("github_repo_stars", "GitHub stars of the package repository.", func(packageInfo ohpm.PackageInfo) (int, bool) {
+		return packageInfo.GithubBaseInfo.StargazersCount, packageInfo.Code == 1 && packageInfo.GithubRepo != ""
+	}, func(packageInfo ohpm.PackageInfo) string {
+		return `,repo="` + escapeLabelValue(packageInfo.GithubUser+"/"+packageInfo.GithubRepo) + `"`
+	})
+
+	hostNames := make([]string, 0, len(hosts))
+	for host := range hosts {
+		hostNames = append(hostNames, host)
+	}
+	sort.Strings(hostNames)
+	out.WriteString("# HELP ohpm_dashboard_http_requests_total HTTP request attempts, including retries.\n")
+	out.WriteString("# TYPE ohpm_dashboard_http_requests_total counter\n")
+	for _, host := range hostNames {
+		out.WriteString(`ohpm_dashboard_http_requests_total{host="` + escapeLabelValue(host) + `"} ` + strconv.Itoa(hosts[host].Requests) + "\n")
+	}
+	out.WriteString("# HELP ohpm_dashboard_http_request_errors_total Failed HTTP request attempts (transport errors, 403, 429, 5xx).\n")
+	out.WriteString("# TYPE ohpm_dashboard_http_request_errors_total counter\n")
+	for _, host := range hostNames {
+		out.WriteString(`ohpm_dashboard_http_request_errors_total{host="` + escapeLabelValue(host) + `"} ` + strconv.Itoa(hosts[host].Errors) + "\n")
+	}
+	out.WriteString("# HELP ohpm_dashboard_http_request_duration_seconds HTTP request attempt duration.\n")
+	out.WriteString("# TYPE ohpm_dashboard_http_request_duration_seconds summary\n")
+	for _, host := range hostNames {
+		label := `{host="` + escapeLabelValue(host) + `"} `
+		out.WriteString("ohpm_dashboard_http_request_duration_seconds_sum" + label + strconv.FormatFloat(hosts[host].DurationSeconds, 'f', -1, 64) + "\n")
+		out.WriteString("ohpm_dashboard_http_request_duration_seconds_count" + label + strconv.Itoa(hosts[host].Requests) + "\n")
+	}
+	return out.String()
+}
+
+// 转义 Prometheus label 值中的 `\`、`"` 与换行
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// 写入 Prometheus textfile collector 文件
+//
+// 先写临时文件再重命名，避免 node_exporter 读到不完整的内容。
+//
+// 参数:
+//   - [filename] 输出文件，如 "/var/lib/node_exporter/ohpm.prom"
+//   - [metrics]  指标内容
+func writeMetricsFile(filename string, metrics string) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(filename), ".ohpm-dashboard-*.prom")
+	if err != nil {
+		return fmt.Errorf("📈❌ writeMetricsFile: %w", err)
+	}
+	defer os.Remove(tempFile.Name())
+	if _, err := tempFile.WriteString(metrics); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("📈❌ writeMetricsFile: Error writing a file: %w", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("📈❌ writeMetricsFile: Error writing a file: %w", err)
+	}
+	if err := os.Chmod(tempFile.Name(), 0644); err != nil {
+		return fmt.Errorf("📈❌ writeMetricsFile: %w", err)
+	}
+	if err := os.Rename(tempFile.Name(), filename); err != nil {
+		return fmt.Errorf("📈❌ writeMetricsFile: %w", err)
+	}
+	slog.Info("📈✅ writeMetricsFile: Success", "file", filename)
+	return nil
+}
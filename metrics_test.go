@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestAssemblePrometheusMetrics(t *testing.T) {
+	list := []ohpm.PackageInfo{
+		{Code: 1, Name: "@a/b", PublisherId: "p1", Downloads: 1200, Likes: 3, Points: 40, MaxPoints: 50, Popularity: 9, Dependents: 2, HealthScore: 85,
+			GithubUser: "u", GithubRepo: "r", GithubBaseInfo: ohpm.GithubBaseInfo{StargazersCount: 7}},
+		{Code: 0, Name: `weird"name`},
+		{Code: 1, Name: "@a/b", Downloads: 999}, // 重复 package 只输出首个
+	}
+	hosts := map[string]httpHostMetrics{"ohpm.openharmony.cn": {Requests: 4, Errors: 1, DurationSeconds: 1.5}}
+	got := assemblePrometheusMetrics(list, hosts)
+
+	for _, want := range []string{
+		"# TYPE ohpm_package_downloads gauge\n",
+		`ohpm_package_found{package="@a/b",publisher="p1"} 1`,
+		`ohpm_package_found{package="weird\"name",publisher=""} 0`,
+		`ohpm_package_downloads{package="@a/b",publisher="p1"} 1200`,
+		`ohpm_package_likes{package="@a/b",publisher="p1"} 3`,
+		`ohpm_package_points{package="@a/b",publisher="p1"} 40`,
+		`ohpm_package_max_points{package="@a/b",publisher="p1"} 50`,
+		`ohpm_package_popularity{package="@a/b",publisher="p1"} 9`,
+		`ohpm_package_dependents{package="@a/b",publisher="p1"} 2`,
+		`ohpm_package_health_score{package="@a/b",publisher="p1"} 85`,
+		`github_repo_stars{package="@a/b",publisher="p1",repo="u/r"} 7`,
+		`ohpm_dashboard_http_requests_total{host="ohpm.openharmony.cn"} 4`,
+		`ohpm_dashboard_http_request_errors_total{host="ohpm.openharmony.cn"} 1`,
+		`ohpm_dashboard_http_request_duration_seconds_sum{host="ohpm.openharmony.cn"} 1.5`,
+		`ohpm_dashboard_http_request_duration_seconds_count{host="ohpm.openharmony.cn"} 4`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q", want)
+		}
+	}
+	if strings.Contains(got, " 999\n") {
+		t.Error("duplicate package must be skipped")
+	}
+	if strings.Contains(got, `ohpm_package_downloads{package="weird`) {
+		t.Error("not found package must only export ohpm_package_found")
+	}
+}
+
+func TestHTTPMetricsObserve(t *testing.T) {
+	metrics := &httpMetrics{hosts: map[string]httpHostMetrics{}}
+	metrics.observe("a", time.Second, false)
+	metrics.observe("a", 500*time.Millisecond, true)
+	got := metrics.snapshot()["a"]
+	if got.Requests != 2 || got.Errors != 1 || got.DurationSeconds != 1.5 {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestWriteMetricsFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "ohpm.prom")
+	if err := writeMetricsFile(filename, "a 1\n"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := os.ReadFile(filename)
+	if err != nil || string(got) != "a 1\n" {
+		t.Errorf("got %q, err %v", got, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(filename))
+	if len(entries) != 1 {
+		t.Errorf("temp file left behind: %v", entries)
+	}
+}
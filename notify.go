@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AmosHuKe/ohpm-dashboard/dashboard"
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// 通知事件类型
+const (
+	notifyEventNewVersion         = "newVersion"         // 发布了新版本
+	notifyEventPointsBelowMax     = "pointsBelowMax"     // points 从满分下降
+	notifyEventNotFound           = "notFound"           // package 无法获取（Code 变为 0）
+	notifyEventDownloadsMilestone = "downloadsMilestone" // 下载量跨过里程碑
+)
+
+// 通知事件（由本次运行与上一次快照对比得出）
+type NotifyEvent struct {
+	Type    string `json:"type"`
+	Package string `json:"package"`
+	Message string `json:"message"`
+	Url     string `json:"url"`
+}
+
+// Webhook 目标
+type WebhookTarget struct {
+	Format string // generic | dingtalk | feishu | slack
+	Url    string
+}
+
+// 支持的 Webhook 负载格式
+var webhookFormats = []string{"generic", "dingtalk", "feishu", "slack"}
+
+// 解析 Webhook 列表
+//
+// 每项为 URL 或 `{format}=URL`，如 "https://a.com/hook,slack=https://hooks.slack.com/xxx"，
+// 未指定格式时为 generic。
+//
+// 参数:
+//   - [webhookList] Webhook 列表（逗号,分割）
+//
+// 返回值:
+//   - [WebhookTarget] 列表
+func parseWebhookTargets(webhookList string) ([]WebhookTarget, error) {
+	targets := []WebhookTarget{}
+	for _, item := range ohpm.SplitList(webhookList) {
+		target := WebhookTarget{Format: "generic", Url: item}
+		// URL 的 query 中也可能包含 `=`，仅当前缀是已知格式时才视为格式
+		if format, rawURL, ok := strings.Cut(item, "="); ok {
+			for _, value := range webhookFormats {
+				if format == value {
+					target = WebhookTarget{Format: format, Url: rawURL}
+					break
+				}
+			}
+		}
+		if parsed, err := url.Parse(target.Url); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return nil, fmt.Errorf("🔔❌ parseWebhookTargets: invalid webhook url %q", target.Url)
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// 解析下载量里程碑
+//
+// 参数:
+//   - [milestoneList] 里程碑列表（逗号,分割），如 "1000,10000"
+//
+// 返回值:
+//   - 升序的里程碑列表
+func parseMilestones(milestoneList string) ([]int, error) {
+	milestones := []int{}
+	for _, item := range ohpm.SplitList(milestoneList) {
+		milestone, err := strconv.Atoi(item)
+		if err != nil || milestone <= 0 {
+			return nil, fmt.Errorf("🔔❌ parseMilestones: invalid milestone %q", item)
+		}
+		milestones = append(milestones, milestone)
+	}
+	sort.Ints(milestones)
+	return milestones, nil
+}
+
+// 对比上一次快照，得出通知事件
+//
+// 上一次快照中不存在的 package 视为首次出现，不产生事件。
+//
+// 参数:
+//   - [previous]   上一次快照中的信息列表
+//   - [current]    本次信息列表
+//   - [milestones] 升序的下载量里程碑
+//
+// 返回值:
+//   - [NotifyEvent] 列表（按 current 顺序）
+func detectNotifyEvents(previous []ohpm.PackageInfo, current []ohpm.PackageInfo, milestones []int) []NotifyEvent {
+	previousMap := make(map[string]ohpm.PackageInfo, len(previous))
+	for _, value := range previous {
+		previousMap[ohpm.WithRegistryPrefix(value.Registry, value.Name)] = value
+	}
+
+	events := []NotifyEvent{}
+	for _, value := range current {
+		before, ok := previousMap[ohpm.WithRegistryPrefix(value.Registry, value.Name)]
+		if !ok {
+			continue
+		}
+		detailURL := value.DetailURL()
+		event := func(eventType string, message string) {
+			events = append(events, NotifyEvent{Type: eventType, Package: value.Name, Message: message, Url: detailURL})
+		}
+
+		if before.Code == 1 && value.Code == 0 {
+			event(notifyEventNotFound, value.Name+" can no longer be found on ohpm")
+			continue
+		}
+		if value.Code == 0 {
+			continue
+		}
+		if before.Code == 1 && value.Version != before.Version {
+			event(notifyEventNewVersion, value.Name+" v"+value.Version+" published (was v"+before.Version+")")
+		}
+		wasFull := before.Code == 1 && before.Points >= before.MaxPoints
+		if wasFull && value.MaxPoints > 0 && value.Points < value.MaxPoints {
+			event(notifyEventPointsBelowMax, value.Name+" points dropped to "+strconv.Itoa(value.Points)+"/"+strconv.Itoa(value.MaxPoints))
+		}
+		// 一次跨过多个里程碑时只通知最大的一个
+		crossed := 0
+		for _, milestone := range milestones {
+			if before.Downloads < milestone && value.Downloads >= milestone {
+				crossed = milestone
+			}
+		}
+		if crossed > 0 {
+			event(notifyEventDownloadsMilestone, value.Name+" reached "+dashboard.FormatNumber(crossed)+" downloads")
+		}
+	}
+	return events
+}
+
+// 组装 Webhook 负载
+//
+// 参数:
+//   - [format] 负载格式，见 [webhookFormats]
+//   - [events] 通知事件
+//
+// 返回值:
+//   - JSON 负载
+func assembleWebhookPayload(format string, events []NotifyEvent) ([]byte, error) {
+	lines := []string{}
+	for _, event := range events {
+		lines = append(lines, "- "+event.Message)
+	}
+	title := "ohpm-dashboard: " + strconv.Itoa(len(events)) + " event(s)"
+	text := title + "\n" + strings.Join(lines, "\n")
+
+	var payload any
+	switch format {
+	case "dingtalk":
+		markdownLines := []string{}
+		for _, event := range events {
+			markdownLines = append(markdownLines, "- ["+event.Message+"]("+event.Url+")")
+		}
+		payload = map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]string{"title": title, "text": "### " + title + "\n\n" + strings.Join(markdownLines, "\n")},
+		}
+	case "feishu":
+		payload = map[string]any{
+			"msg_type": "text",
+			"content":  map[string]string{"text": text},
+		}
+	case "slack":
+		payload = map[string]string{"text": text}
+	default:
+		payload = map[string]any{"events": events}
+	}
+	return json.Marshal(payload)
+}
+
+// 将通知事件发送到所有 Webhook
+//
+// 单个 Webhook 失败不影响其余发送，所有错误合并返回。
+//
+// 参数:
+//   - [ctx]     上下文
+//   - [client]  共享 HTTP Client
+//   - [targets] Webhook 目标
+//   - [events]  通知事件（为空时不发送）
+func sendNotifications(ctx context.Context, client *http.Client, targets []WebhookTarget, events []NotifyEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	var errs []error
+	for _, target := range targets {
+		payload, err := assembleWebhookPayload(target.Format, events)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := postJSON(ctx, client, target.Url, payload); err != nil {
+			// 不输出完整 URL，Webhook URL 中通常带有密钥
+			errs = append(errs, fmt.Errorf("🔔⚠️ sendNotifications: %s webhook (%s): %w", target.Format, webhookHost(target.Url), err))
+			continue
+		}
+		slog.Info("🔔✅ sendNotifications", "format", target.Format, "host", webhookHost(target.Url), "events", len(events))
+	}
+	return errors.Join(errs...)
+}
+
+// 发送 JSON POST 请求，非 2xx 视为失败
+func postJSON(ctx context.Context, client *http.Client, rawURL string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(payload))
+	if err != nil {
+		return errors.New("invalid request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err // 去掉 url.Error 中的完整 URL
+		}
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// 获取 Webhook 的 host（用于日志）
+func webhookHost(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "-"
+	}
+	return parsed.Host
+}
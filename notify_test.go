@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestParseWebhookTargets(t *testing.T) {
+	targets, err := parseWebhookTargets("https://a.com/hook?x=1, slack=https://hooks.slack.com/T,dingtalk=https://oapi.dingtalk.com/robot/send?access_token=t")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []WebhookTarget{
+		{Format: "generic", Url: "https://a.com/hook?x=1"},
+		{Format: "slack", Url: "https://hooks.slack.com/T"},
+		{Format: "dingtalk", Url: "https://oapi.dingtalk.com/robot/send?access_token=t"},
+	}
+	if len(targets) != len(want) {
+		t.Fatalf("targets = %v", targets)
+	}
+	for i := range want {
+		if targets[i] != want[i] {
+			t.Errorf("targets[%d] = %v, want %v", i, targets[i], want[i])
+		}
+	}
+
+	if targets, err := parseWebhookTargets(""); err != nil || len(targets) != 0 {
+		t.Errorf("empty list = %v, %v", targets, err)
+	}
+	for _, input := range []string{"teams=https://a.com", "ftp://a.com", "not a url"} {
+		if _, err := parseWebhookTargets(input); err == nil {
+			t.Errorf("parseWebhookTargets(%q) expected error", input)
+		}
+	}
+}
+
+func TestParseMilestones(t *testing.T) {
+	milestones, err := parseMilestones("10000, 1000")
+	if err != nil || len(milestones) != 2 || milestones[0] != 1000 || milestones[1] != 10000 {
+		t.Errorf("milestones = %v, %v", milestones, err)
+	}
+	for _, input := range []string{"abc", "0", "-5"} {
+		if _, err := parseMilestones(input); err == nil {
+			t.Errorf("parseMilestones(%q) expected error", input)
+		}
+	}
+}
+
+func TestDetectNotifyEvents(t *testing.T) {
+	previous := []ohpm.PackageInfo{
+		{Code: 1, Name: "release", Version: "1.0.0", Points: 100, MaxPoints: 100},
+		{Code: 1, Name: "points", Version: "1.0.0", Points: 100, MaxPoints: 100},
+		{Code: 1, Name: "stillLow", Version: "1.0.0", Points: 80, MaxPoints: 100},
+		{Code: 1, Name: "gone", Version: "1.0.0"},
+		{Code: 0, Name: "stillGone"},
+		{Code: 1, Name: "downloads", Version: "1.0.0", Downloads: 900},
+	}
+	current := []ohpm.PackageInfo{
+		{Code: 1, Name: "release", Version: "1.1.0", Points: 100, MaxPoints: 100},
+		{Code: 1, Name: "points", Version: "1.0.0", Points: 90, MaxPoints: 100},
+		{Code: 1, Name: "stillLow", Version: "1.0.0", Points: 70, MaxPoints: 100},
+		{Code: 0, Name: "gone"},
+		{Code: 0, Name: "stillGone"},
+		{Code: 1, Name: "downloads", Version: "1.0.0", Downloads: 12000},
+		// 首次出现
+		{Code: 1, Name: "new", Version: "1.0.0", Downloads: 5000},
+	}
+	events := detectNotifyEvents(previous, current, []int{1000, 10000, 100000})
+	got := []string{}
+	for _, event := range events {
+		got = append(got, event.Type+":"+event.Package)
+	}
+	want := "newVersion:release,pointsBelowMax:points,notFound:gone,downloadsMilestone:downloads"
+	if strings.Join(got, ",") != want {
+		t.Errorf("events = %v, want %s", got, want)
+	}
+	if events[3].Message != "downloads reached 10k downloads" {
+		t.Errorf("milestone message = %q", events[3].Message)
+	}
+	if len(detectNotifyEvents(current, current, []int{1000})) != 0 {
+		t.Error("unchanged snapshot must not produce events")
+	}
+}
+
+func TestAssembleWebhookPayload(t *testing.T) {
+	events := []NotifyEvent{{Type: notifyEventNewVersion, Package: "@a/b", Message: "@a/b v1.1.0 published (was v1.0.0)", Url: "https://ohpm/x"}}
+	tests := []struct {
+		format string
+		check  func(map[string]any) bool
+	}{
+		{"generic", func(v map[string]any) bool {
+			list, ok := v["events"].([]any)
+			return ok && len(list) == 1 && list[0].(map[string]any)["type"] == "newVersion"
+		}},
+		{"dingtalk", func(v map[string]any) bool {
+			markdown, ok := v["markdown"].(map[string]any)
+			return v["msgtype"] == "markdown" && ok && strings.Contains(markdown["text"].(string), "[@a/b v1.1.0 published (was v1.0.0)](https://ohpm/x)")
+		}},
+		{"feishu", func(v map[string]any) bool {
+			content, ok := v["content"].(map[string]any)
+			return v["msg_type"] == "text" && ok && strings.Contains(content["text"].(string), "- @a/b v1.1.0")
+		}},
+		{"slack", func(v map[string]any) bool {
+			text, ok := v["text"].(string)
+			return ok && strings.HasPrefix(text, "ohpm-dashboard: 1 event(s)\n")
+		}},
+	}
+	for _, tt := range tests {
+		data, err := assembleWebhookPayload(tt.format, events)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.format, err)
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(data, &payload); err != nil {
+			t.Fatalf("%s: invalid json: %v", tt.format, err)
+		}
+		if !tt.check(payload) {
+			t.Errorf("%s: unexpected payload %s", tt.format, data)
+		}
+	}
+}
+
+func TestSendNotifications(t *testing.T) {
+	var mutex sync.Mutex
+	received := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mutex.Lock()
+		received[r.URL.Path] = r.Header.Get("Content-Type") + " " + string(body)
+		mutex.Unlock()
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	events := []NotifyEvent{{Type: notifyEventNotFound, Package: "@a/b", Message: "@a/b can no longer be found on ohpm"}}
+	targets := []WebhookTarget{
+		{Format: "generic", Url: srv.URL + "/generic"},
+		{Format: "slack", Url: srv.URL + "/fail?token=secret"},
+		{Format: "feishu", Url: srv.URL + "/feishu"},
+	}
+	err := sendNotifications(context.Background(), srv.Client(), targets, events)
+	if err == nil || !strings.Contains(err.Error(), "unexpected status 400") {
+		t.Fatalf("err = %v, want status error", err)
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("error leaks webhook url: %v", err)
+	}
+	// 单个失败不影响其余发送
+	if len(received) != 3 {
+		t.Fatalf("received %d requests, want 3", len(received))
+	}
+	if !strings.HasPrefix(received["/generic"], `application/json {"events":[{"type":"notFound"`) {
+		t.Errorf("generic body = %q", received["/generic"])
+	}
+	if !strings.Contains(received["/feishu"], `"msg_type":"text"`) {
+		t.Errorf("feishu body = %q", received["/feishu"])
+	}
+
+	// 无事件时不发送
+	received = map[string]string{}
+	if err := sendNotifications(context.Background(), srv.Client(), targets, nil); err != nil || len(received) != 0 {
+		t.Errorf("no events: err = %v, received = %v", err, received)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "state.json")
+	if _, ok, err := readSnapshot(filename); ok || err != nil {
+		t.Fatalf("missing file: ok = %v, err = %v", ok, err)
+	}
+	generatedAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	list := []ohpm.PackageInfo{{Code: 1, Name: "@a/b", Version: "1.0.0", Downloads: 42}}
+	if err := writeSnapshot(filename, Snapshot{GeneratedAt: generatedAt, Packages: list}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	snapshot, ok, err := readSnapshot(filename)
+	if !ok || err != nil {
+		t.Fatalf("ok = %v, err = %v", ok, err)
+	}
+	if !snapshot.GeneratedAt.Equal(generatedAt) || len(snapshot.Packages) != 1 || snapshot.Packages[0].Downloads != 42 {
+		t.Errorf("snapshot = %+v", snapshot)
+	}
+}
@@ -0,0 +1,580 @@
+package ohpm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Github 主机（github.com 或 GitHub Enterprise Server）
+type GithubHost struct {
+	WebURL string `json:"webURL"` // 网页地址，如 "https://github.example.com"，按其域名识别仓库链接
+	APIURL string `json:"apiURL"` // API 地址，为空时为 WebURL + "/api/v3"
+	Token  string `json:"token"`  // 该主机的令牌
+}
+
+// 获取全部 Github 主机，GitHub Enterprise Server 在前，github.com（[Client.GithubWebURL]）在最后
+func (c *Client) githubHosts() []GithubHost {
+	hosts := []GithubHost{}
+	for _, host := range c.GithubHosts {
+		host.WebURL = strings.TrimSuffix(host.WebURL, "/")
+		if host.APIURL == "" {
+			host.APIURL = host.WebURL + "/api/v3"
+		}
+		hosts = append(hosts, host)
+	}
+	return append(hosts, GithubHost{WebURL: strings.TrimSuffix(c.GithubWebURL, "/"), APIURL: c.GithubAPIURL, Token: c.GithubToken})
+}
+
+// 每个 package 最多发起的 Github 请求数（基础信息、贡献者、Release、Tag）
+const githubRequestsPerPackage = 4
+
+// Github 限流信息（仅取 REST API 的 core 额度）
+type githubRateLimit struct {
+	Resources struct {
+		Core struct {
+			Remaining int   `json:"remaining"`
+			Reset     int64 `json:"reset"` // 额度重置时间（Unix 秒）
+		} `json:"core"`
+	} `json:"resources"`
+}
+
+// 匿名访问时 Github 主机的剩余额度
+type githubQuota struct {
+	remaining int       // 剩余请求数，-1 为不限（未启用限流或额度未知）
+	reset     time.Time // 额度重置时间，之后重新获取（零值表示不再获取）
+}
+
+// 获取 Github 主机的剩余额度
+//
+// 参数:
+//   - [ctx]  上下文
+//   - [host] Github 主机
+//
+// 返回值:
+//   - 剩余额度（主机未启用限流时为不限）
+func (c *Client) githubRemaining(ctx context.Context, host GithubHost) (githubQuota, error) {
+	printErrTitle := "🐙⚠️ GithubRateLimit: "
+	body, status, err := c.get(ctx, host.APIURL+"/rate_limit", githubHeaders(host))
+	if err != nil {
+		return githubQuota{}, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	if status == http.StatusNotFound {
+		return githubQuota{remaining: -1}, nil // GitHub Enterprise Server 未启用限流
+	}
+	if status != http.StatusOK {
+		return githubQuota{}, fmt.Errorf("%s%s: unexpected status %d", printErrTitle, host.APIURL, status)
+	}
+	var data githubRateLimit
+	if err := json.Unmarshal(body, &data); err != nil {
+		return githubQuota{}, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	return githubQuota{remaining: data.Resources.Core.Remaining, reset: time.Unix(data.Resources.Core.Reset, 0)}, nil
+}
+
+// 为一个 package 预留 Github 额度
+//
+// 仅匿名访问（主机无令牌）时生效：首次调用及额度重置后通过 `/rate_limit` 获取剩余额度（该接口不计入额度），
+// 之后每个 package 预留 [githubRequestsPerPackage] 次请求，额度不足时返回 false。
+// 额度无法获取时照常请求，由 [Client.githubInfo] 在被限流时降级。
+// 获取额度时不持有锁：同一主机只有一个 goroutine 请求，其他 goroutine 等待其完成，其他主机不受影响。
+//
+// 参数:
+//   - [ctx]  上下文
+//   - [host] Github 主机
+//
+// 返回值:
+//   - 是否可以获取该 package 的 Github 信息
+func (c *Client) reserveGithubQuota(ctx context.Context, host GithubHost) bool {
+	if host.Token != "" {
+		return true
+	}
+	for {
+		c.githubQuotaMutex.Lock()
+		if c.githubQuotas == nil {
+			c.githubQuotas = map[string]githubQuota{}
+		}
+		if c.githubQuotaFetch == nil {
+			c.githubQuotaFetch = map[string]chan struct{}{}
+		}
+		quota, ok := c.githubQuotas[host.APIURL]
+		if ok && (quota.reset.IsZero() || time.Now().Before(quota.reset)) {
+			reserved := c.takeGithubQuota(host, quota)
+			c.githubQuotaMutex.Unlock()
+			return reserved
+		}
+		if fetching, ok := c.githubQuotaFetch[host.APIURL]; ok {
+			// 其他 goroutine 正在获取 -> 等待后重新检查
+			c.githubQuotaMutex.Unlock()
+			select {
+			case <-fetching:
+				continue
+			case <-ctx.Done():
+				return true // 请求随 ctx 失败
+			}
+		}
+		fetching := make(chan struct{})
+		c.githubQuotaFetch[host.APIURL] = fetching
+		c.githubQuotaMutex.Unlock()
+
+		quota = c.fetchGithubQuota(ctx, host)
+
+		c.githubQuotaMutex.Lock()
+		delete(c.githubQuotaFetch, host.APIURL)
+		close(fetching)
+		reserved := c.takeGithubQuota(host, quota)
+		c.githubQuotaMutex.Unlock()
+		return reserved
+	}
+}
+
+// 获取 Github 主机的剩余额度并输出日志，见 [Client.githubRemaining]
+//
+// 参数:
+//   - [ctx]  上下文
+//   - [host] Github 主机
+//
+// 返回值:
+//   - 剩余额度（无法获取时为不限，一分钟后重新获取）
+func (c *Client) fetchGithubQuota(ctx context.Context, host GithubHost) githubQuota {
+	quota, err := c.githubRemaining(ctx, host)
+	switch {
+	case err != nil:
+		c.logger().Warn(err.Error())
+		quota = githubQuota{remaining: -1, reset: time.Now().Add(time.Minute)} // 额度未知 -> 照常请求，稍后重新获取
+	case quota.remaining < 0:
+		// 未启用限流
+	case quota.remaining < githubRequestsPerPackage:
+		c.logger().Warn("🐙⚠️ Github rate limit exhausted, skipping GitHub data", "host", host.WebURL, "reset", quota.reset)
+	default:
+		c.logger().Info("🐙 Github anonymous access", "host", host.WebURL, "remaining", quota.remaining)
+	}
+	return quota
+}
+
+// 从 [quota] 中预留一个 package 的请求数并保存（调用方持有 githubQuotaMutex）
+//
+// 参数:
+//   - [host]  Github 主机
+//   - [quota] 当前额度
+//
+// 返回值:
+//   - 额度是否足够
+func (c *Client) takeGithubQuota(host GithubHost, quota githubQuota) bool {
+	switch {
+	case quota.remaining < 0:
+		// 不限
+	case quota.remaining < githubRequestsPerPackage:
+		c.githubQuotas[host.APIURL] = githubQuota{remaining: 0, reset: quota.reset}
+		return false
+	default:
+		quota.remaining -= githubRequestsPerPackage
+	}
+	c.githubQuotas[host.APIURL] = quota
+	return true
+}
+
+// 匿名访问时请求被限流：耗尽该主机的额度，后续 package 不再请求
+//
+// 参数:
+//   - [host] Github 主机
+func (c *Client) exhaustGithubQuota(host GithubHost) {
+	c.githubQuotaMutex.Lock()
+	defer c.githubQuotaMutex.Unlock()
+	if c.githubQuotas == nil {
+		c.githubQuotas = map[string]githubQuota{}
+	}
+	quota := c.githubQuotas[host.APIURL]
+	if quota.remaining != 0 {
+		c.logger().Warn("🐙⚠️ Github rate limit exhausted, skipping GitHub data", "host", host.WebURL)
+	}
+	// 重置时间未知时（未启用限流或额度未知）一小时后重新获取，与 GitHub 的限流窗口一致
+	if quota.reset.IsZero() || time.Now().After(quota.reset) {
+		quota.reset = time.Now().Add(time.Hour)
+	}
+	c.githubQuotas[host.APIURL] = githubQuota{remaining: 0, reset: quota.reset}
+}
+
+// 每个 package 对应 Github 仓库的最新 Release 信息
+type githubReleaseInfo struct {
+	TagName string `json:"tag_name"`
+}
+
+// 每个 package 对应 Github 仓库的 Tag 信息
+type githubTagInfo struct {
+	Name string `json:"name"`
+}
+
+// 获取 Github 信息，
+// 处理 [PackageInfo] 中 GithubUser, GithubRepo, GithubBaseInfo, GithubContributorsInfo 的值
+//
+// 参数:
+//   - [ctx]         上下文
+//   - [packageInfo] 当前 package 信息
+func (c *Client) githubInfo(ctx context.Context, packageInfo *PackageInfo) error {
+	if packageInfo.Code == 0 {
+		return nil
+	}
+	// 依次尝试 Repository、Homepage 解析 Github 地址（按主机域名匹配），取首个命中
+	var host GithubHost
+	hosts := c.githubHosts()
+	for _, link := range []string{packageInfo.Repository, packageInfo.Homepage} {
+		for _, value := range hosts {
+			if user, repo := formatGithubInfo(link, value.WebURL); repo != "" {
+				host = value
+				packageInfo.GithubUser = user
+				packageInfo.GithubRepo = repo
+				packageInfo.GithubWebURL = value.WebURL
+				break
+			}
+		}
+		if packageInfo.GithubRepo != "" {
+			break
+		}
+	}
+	if packageInfo.GithubUser == "" || packageInfo.GithubRepo == "" {
+		return nil
+	}
+	if !c.reserveGithubQuota(ctx, host) {
+		packageInfo.GithubSkipped = true
+		return nil
+	}
+	err := c.githubDetail(ctx, host, packageInfo)
+	if err != nil && host.Token == "" && isRateLimited(err) {
+		// 匿名访问被限流 -> 降级为仅链接，不中断运行
+		c.exhaustGithubQuota(host)
+		packageInfo.GithubBaseInfo = GithubBaseInfo{}
+		packageInfo.GithubContributorsInfo = nil
+		packageInfo.GithubLatestVersion = ""
+		packageInfo.GithubSkipped = true
+		return nil
+	}
+	return err
+}
+
+// 获取 Github 仓库的基础信息、贡献者与最新版本
+//
+// 参数:
+//   - [ctx]         上下文
+//   - [host]        Github 主机
+//   - [packageInfo] 当前 package 信息（GithubUser、GithubRepo 已解析）
+func (c *Client) githubDetail(ctx context.Context, host GithubHost, packageInfo *PackageInfo) error {
+	githubBaseInfo, err := c.githubBaseInfo(ctx, host, packageInfo.GithubUser, packageInfo.GithubRepo)
+	if err != nil {
+		return err
+	}
+	packageInfo.GithubBaseInfo = githubBaseInfo
+	if githubBaseInfo.NotFound {
+		return nil
+	}
+
+	githubContributorsInfo, contributorsTotal, err := c.githubContributorsInfo(ctx, host, packageInfo.GithubUser, packageInfo.GithubRepo)
+	if err != nil {
+		return err
+	}
+	packageInfo.GithubContributorsInfo = githubContributorsInfo
+	packageInfo.GithubBaseInfo.ContributorsTotal = contributorsTotal
+
+	githubLatestVersion, err := c.githubLatestVersion(ctx, host, packageInfo.GithubUser, packageInfo.GithubRepo, packageInfo.Name)
+	if err != nil {
+		return err
+	}
+	packageInfo.GithubLatestVersion = githubLatestVersion
+	return nil
+}
+
+// 构造 GitHub API 通用请求头
+//
+// 参数:
+//   - [host] Github 主机（使用该主机的令牌，为空时匿名访问，不发送 Authorization）
+func githubHeaders(host GithubHost) map[string]string {
+	headers := map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2026-03-10",
+	}
+	if host.Token != "" {
+		headers["Authorization"] = "bearer " + host.Token
+	}
+	return headers
+}
+
+// 获取 Github 基础信息
+//
+// 参数:
+//   - [ctx]  上下文
+//   - [host] Github 主机
+//   - [user] 用户
+//   - [repo] 仓库
+//
+// 返回值:
+//   - [GithubBaseInfo] 信息（404 时降级为空）
+func (c *Client) githubBaseInfo(ctx context.Context, host GithubHost, user string, repo string) (GithubBaseInfo, error) {
+	printErrTitle := "📦⚠️ GithubBaseInfo: "
+	rawURL := fmt.Sprintf("%s/repos/%s/%s", host.APIURL, user, repo)
+	body, status, err := c.get(ctx, rawURL, githubHeaders(host))
+	if err != nil {
+		return GithubBaseInfo{}, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	if status == http.StatusNotFound {
+		return GithubBaseInfo{NotFound: true}, nil // 仓库不存在 -> 降级
+	}
+	if status != http.StatusOK {
+		return GithubBaseInfo{}, fmt.Errorf("%s%s/%s: unexpected status %d", printErrTitle, user, repo, status)
+	}
+	var data GithubBaseInfo
+	if err := json.Unmarshal(body, &data); err != nil {
+		return GithubBaseInfo{}, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	return data, nil
+}
+
+// 获取 Github 贡献者信息
+//
+// 参数:
+//   - [ctx]  上下文
+//   - [host] Github 主机
+//   - [user] 用户
+//   - [repo] 仓库
+//
+// 返回值:
+//   - [GithubContributorsInfo] 贡献者列表（前 3 位非 Bot）
+//   - 贡献者总数（最多 100；404/204 时为 0）
+func (c *Client) githubContributorsInfo(ctx context.Context, host GithubHost, user string, repo string) ([]GithubContributorsInfo, int, error) {
+	printErrTitle := "📦⚠️ GithubContributorsInfo: "
+	rawURL := fmt.Sprintf("%s/repos/%s/%s/contributors?page=1&per_page=100", host.APIURL, user, repo)
+	body, status, err := c.get(ctx, rawURL, githubHeaders(host))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	// 404（仓库不存在）/ 204（空仓库，无贡献者）-> 降级
+	if status == http.StatusNotFound || status == http.StatusNoContent {
+		return nil, 0, nil
+	}
+	if status != http.StatusOK {
+		return nil, 0, fmt.Errorf("%s%s/%s: unexpected status %d", printErrTitle, user, repo, status)
+	}
+	var data []GithubContributorsInfo
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, 0, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+
+	githubContributorsInfo := []GithubContributorsInfo{}
+	i := 1
+	// 取前 3 位非 Bot 贡献者
+	for _, value := range data {
+		if i > 3 {
+			break
+		}
+		if value.Type == "User" {
+			githubContributorsInfo = append(githubContributorsInfo, value)
+			i++
+		}
+	}
+	return githubContributorsInfo, len(data), nil
+}
+
+// 获取 Github 最新版本（tag 名）
+//
+// 优先取最新 Release，仓库无 Release（404）或最新 Release 属于其他 package（monorepo）时，
+// 回退为 Tag 列表中属于该 package 且版本号最大的 Tag，见 [TagVersion]。
+//
+// 参数:
+//   - [ctx]  上下文
+//   - [host] Github 主机
+//   - [user] 用户
+//   - [repo] 仓库
+//   - [name] package 名称
+//
+// 返回值:
+//   - 最新 tag 名（无 Release 且无 Tag 时为空）
+func (c *Client) githubLatestVersion(ctx context.Context, host GithubHost, user string, repo string, name string) (string, error) {
+	printErrTitle := "📦⚠️ GithubLatestVersion: "
+	rawURL := fmt.Sprintf("%s/repos/%s/%s/releases/latest", host.APIURL, user, repo)
+	body, status, err := c.get(ctx, rawURL, githubHeaders(host))
+	if err != nil {
+		return "", fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	if status == http.StatusOK {
+		var data githubReleaseInfo
+		if err := json.Unmarshal(body, &data); err != nil {
+			return "", fmt.Errorf("%s%w", printErrTitle, err)
+		}
+		// 最新 Release 属于 monorepo 中的其他 package 时回退到 Tag
+		if TagVersion(data.TagName, name) != "" || NormalizeVersion(data.TagName) == "" {
+			return data.TagName, nil
+		}
+	} else if status != http.StatusNotFound {
+		return "", fmt.Errorf("%s%s/%s: unexpected status %d", printErrTitle, user, repo, status)
+	}
+
+	// 无 Release（或属于其他 package）-> 回退到 Tag
+	rawURL = fmt.Sprintf("%s/repos/%s/%s/tags?page=1&per_page=100", host.APIURL, user, repo)
+	body, status, err = c.get(ctx, rawURL, githubHeaders(host))
+	if err != nil {
+		return "", fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	if status == http.StatusNotFound {
+		return "", nil // 仓库不存在 -> 降级
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("%s%s/%s: unexpected status %d", printErrTitle, user, repo, status)
+	}
+	var data []githubTagInfo
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	// Tag 列表按名称排序而非版本号，取版本号最大者
+	latest := ""
+	for _, tag := range data {
+		if TagVersion(tag.Name, name) == "" {
+			continue
+		}
+		if latest == "" || CompareVersion(TagVersion(tag.Name, name), TagVersion(latest, name)) > 0 {
+			latest = tag.Name
+		}
+	}
+	return latest, nil
+}
+
+// 格式化 Github 信息
+//
+// 解析链接（如 "https://github.com/user/repo"、"git+https://github.com/user/repo.git"），
+// 域名与 [webURL] 的域名完全相同时取路径中的 user/repo，
+// 避免误匹配 githubXcom、gist.github.com 等相似域名或路径中出现的域名。
+//
+// 参数:
+//   - [value]  Github 链接
+//   - [webURL] Github 主机网页地址，如 "https://github.com"
+//
+// 返回值:
+//   - githubUser 信息
+//   - githubRepo 信息
+func formatGithubInfo(value string, webURL string) (string, string) {
+	host := webURL
+	if parsed, err := url.Parse(webURL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+	link, err := url.Parse(strings.TrimSpace(value))
+	if host == "" || err != nil || link.Scheme == "" || !strings.EqualFold(link.Host, host) {
+		return "", ""
+	}
+	// query/fragment 尾巴（如 ?tab=、#readme）已由 url.Parse 分离，去除 .git 后缀
+	info := strings.Split(strings.TrimPrefix(link.Path, "/"), "/")
+	if len(info) < 2 || info[0] == "" || info[1] == "" {
+		return "", ""
+	}
+	return info[0], strings.TrimSuffix(info[1], ".git")
+}
+
+// 从 tag 名中提取版本号
+//
+// 支持 "1.2.3"、"v1.2.3"、"@candies/extended_text@1.2.3" 等形式。
+//
+// 参数:
+//   - [tag] tag 名
+//
+// 返回值:
+//   - 版本号（无法识别时为空）
+func NormalizeVersion(tag string) string {
+	version := strings.TrimSpace(tag)
+	if i := strings.LastIndex(version, "@"); i >= 0 {
+		version = version[i+1:]
+	}
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	if version == "" || version[0] < '0' || version[0] > '9' {
+		return ""
+	}
+	return version
+}
+
+// 从 tag 名中提取指定 package 的版本号
+//
+// "name@1.2.3" 形式的 tag 仅当 name 与 [name] 相同时有效（monorepo 中其他 package 的 tag 视为无法识别），
+// 其他形式同 [NormalizeVersion]。
+//
+// 参数:
+//   - [tag]  tag 名
+//   - [name] package 名称
+//
+// 返回值:
+//   - 版本号（无法识别或属于其他 package 时为空）
+func TagVersion(tag string, name string) string {
+	tag = strings.TrimSpace(tag)
+	if i := strings.LastIndex(tag, "@"); i > 0 && tag[:i] != name {
+		return ""
+	}
+	return NormalizeVersion(tag)
+}
+
+// 比较两个版本号（semver 风格，忽略 +build 元数据）
+//
+// 参数:
+//   - [a] 版本号
+//   - [b] 版本号
+//
+// 返回值:
+//   - a < b 时为 -1，a == b 时为 0，a > b 时为 1
+func CompareVersion(a string, b string) int {
+	splitVersion := func(v string) (string, string) {
+		if i := strings.Index(v, "+"); i >= 0 {
+			v = v[:i]
+		}
+		if i := strings.Index(v, "-"); i >= 0 {
+			return v[:i], v[i+1:]
+		}
+		return v, ""
+	}
+	compareInt := func(x, y int) int {
+		if x < y {
+			return -1
+		}
+		if x > y {
+			return 1
+		}
+		return 0
+	}
+	coreA, preA := splitVersion(a)
+	coreB, preB := splitVersion(b)
+	partsA := strings.Split(coreA, ".")
+	partsB := strings.Split(coreB, ".")
+	for i := 0; i < max(len(partsA), len(partsB)); i++ {
+		var x, y int
+		if i < len(partsA) {
+			x, _ = strconv.Atoi(partsA[i])
+		}
+		if i < len(partsB) {
+			y, _ = strconv.Atoi(partsB[i])
+		}
+		if result := compareInt(x, y); result != 0 {
+			return result
+		}
+	}
+	// 主版本相同：无预发布标识的版本更大
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	}
+	return strings.Compare(preA, preB)
+}
+
+// Github 最新 Release/Tag 是否领先于 ohpm 已发布版本（即打了 tag 却忘记 `ohpm publish`）
+//
+// 参数:
+//   - [packageInfo] package 信息
+func IsGithubVersionAhead(packageInfo PackageInfo) bool {
+	ohpmVersion := NormalizeVersion(packageInfo.Version)
+	githubVersion := TagVersion(packageInfo.GithubLatestVersion, packageInfo.Name)
+	if packageInfo.Code == 0 || ohpmVersion == "" || githubVersion == "" {
+		return false
+	}
+	return CompareVersion(githubVersion, ohpmVersion) > 0
+}
@@ -0,0 +1,352 @@
+package ohpm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFormatGithubInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		wantUser string
+		wantRepo string
+	}{
+		{"plain https url", "https://github.com/AmosHuKe/ohpm-dashboard", "AmosHuKe", "ohpm-dashboard"},
+		{"with .git suffix", "https://github.com/AmosHuKe/ohpm-dashboard.git", "AmosHuKe", "ohpm-dashboard"},
+		{"with fragment", "https://github.com/AmosHuKe/ohpm-dashboard#readme", "AmosHuKe", "ohpm-dashboard"},
+		{"with query string", "https://github.com/AmosHuKe/ohpm-dashboard?tab=readme", "AmosHuKe", "ohpm-dashboard"},
+		{"with sub path", "https://github.com/AmosHuKe/ohpm-dashboard/tree/main", "AmosHuKe", "ohpm-dashboard"},
+		{"non-github url", "https://ohpm.openharmony.cn/#/cn/detail/@candies/extended_text", "", ""},
+		{"empty", "", "", ""},
+		{"lookalike domain is not github", "https://githubXcom/evil/repo", "", ""},
+		{"git+https url", "git+https://github.com/AmosHuKe/ohpm-dashboard.git", "AmosHuKe", "ohpm-dashboard"},
+		{"gist subdomain is not github", "https://gist.github.com/AmosHuKe/abc123", "", ""},
+		{"github host in path", "https://example.com/github.com/evil/repo", "", ""},
+		{"github host in query", "https://example.com/?from=https://github.com/evil/repo", "", ""},
+		{"user only", "https://github.com/AmosHuKe", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, repo := formatGithubInfo(tt.in, DefaultGithubWebURL)
+			if user != tt.wantUser || repo != tt.wantRepo {
+				t.Errorf("formatGithubInfo(%q) = (%q, %q), want (%q, %q)", tt.in, user, repo, tt.wantUser, tt.wantRepo)
+			}
+		})
+	}
+}
+
+func TestFormatGithubInfoEnterprise(t *testing.T) {
+	user, repo := formatGithubInfo("https://github.example.com/team/app.git", "https://github.example.com")
+	if user != "team" || repo != "app" {
+		t.Errorf("got (%q, %q)", user, repo)
+	}
+	// github.com 不匹配 GitHub Enterprise Server 的链接
+	if _, repo := formatGithubInfo("https://github.example.com/team/app", DefaultGithubWebURL); repo != "" {
+		t.Errorf("github.com matched enterprise link: %q", repo)
+	}
+}
+
+func TestClientGithubHosts(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/team/app", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "bearer ghe" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"stargazers_count":3}`))
+	})
+	mux.HandleFunc("GET /repos/team/app/contributors", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /repos/team/app/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name":"v2.0.0"}`))
+	})
+	enterprise := httptest.NewServer(mux)
+	defer enterprise.Close()
+
+	client := NewClient("public")
+	client.GithubAPIURL = "http://127.0.0.1:1" // github.com 不可达，确保请求未发往 github.com
+	client.MaxAttempts = 1
+	client.GithubHosts = []GithubHost{{WebURL: "https://github.example.com/", APIURL: enterprise.URL, Token: "ghe"}}
+
+	packageInfo := PackageInfo{Code: 1, Homepage: "https://github.example.com/team/app"}
+	if err := client.githubInfo(context.Background(), &packageInfo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if packageInfo.GithubBaseInfo.StargazersCount != 3 || packageInfo.GithubLatestVersion != "v2.0.0" {
+		t.Errorf("got %+v", packageInfo)
+	}
+	if packageInfo.IsGithubCom() || packageInfo.GithubRepoURL() != "https://github.example.com/team/app" {
+		t.Errorf("GithubRepoURL() = %q", packageInfo.GithubRepoURL())
+	}
+
+	// 默认 API 地址：WebURL + /api/v3
+	if hosts := (&Client{GithubHosts: []GithubHost{{WebURL: "https://github.example.com"}}}).githubHosts(); hosts[0].APIURL != "https://github.example.com/api/v3" {
+		t.Errorf("APIURL = %q", hosts[0].APIURL)
+	}
+}
+
+func TestClientGithubAnonymous(t *testing.T) {
+	var requests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rate_limit", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"resources":{"core":{"remaining":5,"reset":4102444800}}}`))
+	})
+	mux.HandleFunc("GET /repos/", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/contributors"):
+			w.WriteHeader(http.StatusNoContent)
+		case strings.HasSuffix(r.URL.Path, "/releases/latest"):
+			w.Write([]byte(`{"tag_name":"v1.0.0"}`))
+		default:
+			w.Write([]byte(`{"stargazers_count":7}`))
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient("")
+	client.GithubAPIURL = server.URL
+	client.MaxAttempts = 1
+
+	// 额度 5：第一个 package 预留 4 次请求，第二个 package 额度不足 -> 仅链接
+	first := PackageInfo{Code: 1, Repository: "https://github.com/a/one"}
+	second := PackageInfo{Code: 1, Repository: "https://github.com/a/two"}
+	for _, packageInfo := range []*PackageInfo{&first, &second} {
+		if err := client.githubInfo(context.Background(), packageInfo); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if first.GithubSkipped || first.GithubBaseInfo.StargazersCount != 7 {
+		t.Errorf("first = %+v", first)
+	}
+	if !second.GithubSkipped || second.GithubRepo != "two" || second.GithubBaseInfo.StargazersCount != 0 {
+		t.Errorf("second = %+v", second)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+}
+
+func TestClientGithubAnonymousRateLimited(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rate_limit", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound) // 未启用限流
+	})
+	mux.HandleFunc("GET /repos/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient("")
+	client.GithubAPIURL = server.URL
+	client.MaxAttempts = 1
+
+	packageInfo := PackageInfo{Code: 1, Repository: "https://github.com/a/one"}
+	if err := client.githubInfo(context.Background(), &packageInfo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !packageInfo.GithubSkipped {
+		t.Errorf("got %+v", packageInfo)
+	}
+	host := client.githubHosts()[0]
+	if client.reserveGithubQuota(context.Background(), host) {
+		t.Error("quota not exhausted after rate limit")
+	}
+
+	// 有令牌时限流仍为错误
+	client = NewClient("token")
+	client.GithubAPIURL = server.URL
+	client.MaxAttempts = 1
+	packageInfo = PackageInfo{Code: 1, Repository: "https://github.com/a/one"}
+	if err := client.githubInfo(context.Background(), &packageInfo); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestClientReserveGithubQuotaConcurrent(t *testing.T) {
+	release := make(chan struct{})
+	releaseOnce := sync.OnceFunc(func() { close(release) })
+	var rateLimits atomic.Int32
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rateLimits.Add(1)
+		<-release
+		w.Write([]byte(`{"resources":{"core":{"remaining":100,"reset":4102444800}}}`))
+	}))
+	defer slow.Close()
+	defer releaseOnce()
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"resources":{"core":{"remaining":100,"reset":4102444800}}}`))
+	}))
+	defer fast.Close()
+
+	client := NewClient("")
+	client.MaxAttempts = 1
+	slowHost := GithubHost{WebURL: "https://slow.example.com", APIURL: slow.URL}
+	fastHost := GithubHost{WebURL: "https://fast.example.com", APIURL: fast.URL}
+
+	// 同一主机并发预留：只请求一次 /rate_limit
+	results := make(chan bool, 3)
+	for range 3 {
+		go func() { results <- client.reserveGithubQuota(context.Background(), slowHost) }()
+	}
+	for rateLimits.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// 获取额度期间不持有锁，其他主机不被阻塞
+	done := make(chan bool)
+	go func() { done <- client.reserveGithubQuota(context.Background(), fastHost) }()
+	select {
+	case reserved := <-done:
+		if !reserved {
+			t.Error("fast host not reserved")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("fast host blocked by the slow /rate_limit request")
+	}
+
+	releaseOnce()
+	for range 3 {
+		if !<-results {
+			t.Error("slow host not reserved")
+		}
+	}
+	if got := rateLimits.Load(); got != 1 {
+		t.Errorf("rate_limit requests = %d, want 1", got)
+	}
+	if got := client.githubQuotas[slowHost.APIURL].remaining; got != 100-3*githubRequestsPerPackage {
+		t.Errorf("remaining = %d", got)
+	}
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"1.2.3", "1.2.3"},
+		{"v1.2.3", "1.2.3"},
+		{"V2.0.0-beta.1", "2.0.0-beta.1"},
+		{"@candies/extended_text@1.1.0", "1.1.0"},
+		{"release-1", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := NormalizeVersion(tt.in); got != tt.want {
+				t.Errorf("NormalizeVersion(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagVersion(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"v1.2.3", "1.2.3"},
+		{"@scope/app@1.2.3", "1.2.3"},
+		{"@scope/other@2.0.0", ""},
+		{"other@2.0.0", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := TagVersion(tt.tag, "@scope/app"); got != tt.want {
+				t.Errorf("TagVersion(%q) = %q, want %q", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareVersion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.1", "1.0.0", 1},
+		{"1.0.0", "1.1.0", -1},
+		{"1.10.0", "1.9.0", 1}, // 数值比较而非字符串比较
+		{"1.0", "1.0.0", 0},
+		{"1.0.0", "1.0.0-beta", 1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0+build.1", "1.0.0", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := CompareVersion(tt.a, tt.b); got != tt.want {
+				t.Errorf("CompareVersion(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsGithubVersionAhead(t *testing.T) {
+	tests := []struct {
+		name string
+		in   PackageInfo
+		want bool
+	}{
+		{"github ahead", PackageInfo{Code: 1, Version: "1.0.0", GithubLatestVersion: "v1.1.0"}, true},
+		{"in sync", PackageInfo{Code: 1, Version: "1.1.0", GithubLatestVersion: "v1.1.0"}, false},
+		{"ohpm ahead", PackageInfo{Code: 1, Version: "1.2.0", GithubLatestVersion: "v1.1.0"}, false},
+		{"no github version", PackageInfo{Code: 1, Version: "1.0.0"}, false},
+		{"unparsable tag", PackageInfo{Code: 1, Version: "1.0.0", GithubLatestVersion: "nightly"}, false},
+		{"package not found", PackageInfo{Code: 0, GithubLatestVersion: "v1.0.0"}, false},
+		{"monorepo own tag", PackageInfo{Code: 1, Name: "@scope/app", Version: "1.0.0", GithubLatestVersion: "@scope/app@1.1.0"}, true},
+		{"monorepo sibling tag", PackageInfo{Code: 1, Name: "@scope/app", Version: "1.0.0", GithubLatestVersion: "@scope/other@2.0.0"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsGithubVersionAhead(tt.in); got != tt.want {
+				t.Errorf("IsGithubVersionAhead(%+v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientGithubLatestVersionMonorepo(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/team/mono/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name":"@scope/other@2.0.0"}`))
+	})
+	mux.HandleFunc("GET /repos/team/mono/tags", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name":"@scope/other@3.0.0"},{"name":"@scope/app@1.2.0"},{"name":"@scope/app@1.10.0"}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient("token")
+	client.MaxAttempts = 1
+	host := GithubHost{WebURL: DefaultGithubWebURL, APIURL: server.URL, Token: "token"}
+
+	// 最新 Release 属于其他 package -> 取 Tag 中属于该 package 的最大版本
+	got, err := client.githubLatestVersion(context.Background(), host, "team", "mono", "@scope/app")
+	if err != nil || got != "@scope/app@1.10.0" {
+		t.Errorf("got %q, %v", got, err)
+	}
+	got, err = client.githubLatestVersion(context.Background(), host, "team", "mono", "@scope/other")
+	if err != nil || got != "@scope/other@2.0.0" {
+		t.Errorf("got %q, %v", got, err)
+	}
+	// 没有属于该 package 的 Tag
+	got, err = client.githubLatestVersion(context.Background(), host, "team", "mono", "@scope/none")
+	if err != nil || got != "" {
+		t.Errorf("got %q, %v", got, err)
+	}
+}
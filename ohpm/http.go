@@ -0,0 +1,217 @@
+package ohpm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// concurrentMap 以 [concurrency] 为上限并发地将 fn 应用到每个 item，
+// 结果按输入顺序写入返回切片（result[i] 对应 items[i]）。
+//
+// 任一 fn 返回错误即取消其余任务并返回首个错误。
+// 由于每个 goroutine 只写入互不重叠的 result[i]，无需对结果加锁。
+//
+// 参数:
+//   - [ctx]         上下文（用于取消与超时传播）
+//   - [items]       输入切片
+//   - [concurrency] 并发数上限
+//   - [fn]          处理函数
+//
+// 返回值:
+//   - [results]     结果切片（与 items 顺序一致）
+//   - [error]       任一 fn 返回错误时非 nil
+func concurrentMap[T any, R any](ctx context.Context, items []T, concurrency int, fn func(context.Context, T) (R, error)) ([]R, error) {
+	results := make([]R, len(items))
+	if len(items) == 0 {
+		return results, nil
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		waitGroup sync.WaitGroup
+		mutex     sync.Mutex
+		firstErr  error
+	)
+	semaphore := make(chan struct{}, concurrency)
+
+	for i, item := range items {
+		waitGroup.Add(1)
+		go func(i int, item T) {
+			defer waitGroup.Done()
+
+			// 并发限流，若已取消则直接放弃
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-semaphore }()
+
+			r, err := fn(ctx, item)
+			if err != nil {
+				mutex.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel() // fail-fast：取消其余在途/未启动任务
+				}
+				mutex.Unlock()
+				return
+			}
+			results[i] = r
+		}(i, item)
+	}
+	waitGroup.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+// 带重试的 HTTP GET 请求
+//
+// 对传输层错误、429 (Too Many Requests)、403 (限流/鉴权)、5xx 进行指数退避重试，
+// 最多尝试 [Client.MaxAttempts] 次；退避期间响应 ctx 取消。
+//
+// 仅负责传输 + 重试瞬时故障，状态码的业务语义（如 404 的含义）
+// 由调用方根据返回的 status 自行解释。
+//
+// 参数:
+//   - [ctx]     上下文（用于取消与超时传播）
+//   - [rawURL]  请求地址
+//   - [headers] 附加请求头（可为 nil）
+//
+// 返回值:
+//   - 响应体
+//   - HTTP 状态码
+//   - 错误（传输层彻底失败或重试耗尽时非 nil）
+func (c *Client) get(ctx context.Context, rawURL string, headers map[string]string) ([]byte, int, error) {
+	maxAttempts := max(c.MaxAttempts, 1)
+	// 记录一次尝试：指标（OnRequest）与 Debug 日志
+	observe := func(req *http.Request, attempt int, start time.Time, status int, err error) {
+		duration := time.Since(start)
+		if c.OnRequest != nil {
+			c.OnRequest(req.URL.Host, duration, err != nil)
+		}
+		attrs := []any{"url", rawURL, "status", status, "attempt", attempt, "duration", duration}
+		if err != nil {
+			attrs = append(attrs, "error", err)
+		}
+		c.logger().DebugContext(ctx, "🌏 HTTP request", attrs...)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		// 退避（首次不等待）：500ms, 1s, 2s ...
+		if attempt > 1 {
+			delay := c.RetryBaseDelay * time.Duration(1<<(attempt-2))
+			select {
+			case <-ctx.Done():
+				return nil, 0, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return nil, 0, err // 构造请求失败不可恢复
+		}
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+
+		start := time.Now()
+		res, err := c.HTTPClient.Do(req)
+		if err != nil {
+			observe(req, attempt, start, 0, err)
+			if ctx.Err() != nil {
+				return nil, 0, ctx.Err() // 已取消则立即返回
+			}
+			lastErr = err
+			continue
+		}
+
+		body, readErr := io.ReadAll(res.Body)
+		res.Body.Close()
+		status := res.StatusCode
+
+		if readErr != nil {
+			observe(req, attempt, start, status, readErr)
+			if ctx.Err() != nil {
+				return nil, status, ctx.Err()
+			}
+			lastErr = readErr
+			continue
+		}
+
+		// 可重试的状态码：限流与服务端错误
+		if status == http.StatusTooManyRequests || status == http.StatusForbidden || status >= 500 {
+			lastErr = statusError{status: status}
+			observe(req, attempt, start, status, lastErr)
+			continue
+		}
+		observe(req, attempt, start, status, nil)
+
+		// 成功或不可重试的状态码（2xx、404 等），交由调用方判断
+		return body, status, nil
+	}
+	return nil, 0, fmt.Errorf("After %d attempts: %w", maxAttempts, lastErr)
+}
+
+// 重试耗尽时最后一次响应的状态码
+type statusError struct {
+	status int
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.status)
+}
+
+// 错误是否为重试耗尽的限流状态码（403 / 429）
+//
+// 参数:
+//   - [err] [Client.get] 返回的错误
+func isRateLimited(err error) bool {
+	var statusErr statusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	return statusErr.status == http.StatusForbidden || statusErr.status == http.StatusTooManyRequests
+}
+
+// decodeBody 解析 OHPM 接口响应外层 {"code":..., "body":...} 中的 body 字段为 T。
+//
+// OHPM 对不存在的资源仍返回 200，但此时 body 是字符串（如 "success"）而非对象，
+//
+// 参数:
+//   - [respBody] 完整响应体
+//
+// 返回值:
+//   - 解析后的 body 内容
+//   - body 是否为可解析的预期对象
+//   - 外层 JSON 解析错误（如非 JSON 响应）
+func decodeBody[T any](respBody []byte) (T, bool, error) {
+	var zero T
+	var envelope struct {
+		Body json.RawMessage `json:"body"`
+	}
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		return zero, false, err
+	}
+	var out T
+	if err := json.Unmarshal(envelope.Body, &out); err != nil {
+		return zero, false, nil
+	}
+	return out, true, nil
+}
@@ -0,0 +1,299 @@
+package ohpm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPGetWithRetry(t *testing.T) {
+	client := NewClient("")
+	client.RetryBaseDelay = time.Millisecond
+
+	t.Run("success on first attempt", func(t *testing.T) {
+		var hits atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"ok":true}`))
+		}))
+		defer srv.Close()
+
+		body, status, err := client.get(context.Background(), srv.URL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if status != http.StatusOK {
+			t.Errorf("status = %d, want 200", status)
+		}
+		if string(body) != `{"ok":true}` {
+			t.Errorf("body = %q", body)
+		}
+		if n := hits.Load(); n != 1 {
+			t.Errorf("hits = %d, want 1", n)
+		}
+	})
+
+	t.Run("retries on 503 then succeeds", func(t *testing.T) {
+		var hits atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hits.Add(1) < int32(DefaultMaxAttempts) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("recovered"))
+		}))
+		defer srv.Close()
+
+		body, status, err := client.get(context.Background(), srv.URL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if status != http.StatusOK || string(body) != "recovered" {
+			t.Errorf("status=%d body=%q, want 200/recovered", status, body)
+		}
+		if n := hits.Load(); n != int32(DefaultMaxAttempts) {
+			t.Errorf("hits = %d, want %d", n, DefaultMaxAttempts)
+		}
+	})
+
+	t.Run("retries on 429 then succeeds", func(t *testing.T) {
+		var hits atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hits.Add(1) < int32(DefaultMaxAttempts) {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("ok"))
+		}))
+		defer srv.Close()
+
+		_, status, err := client.get(context.Background(), srv.URL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if status != http.StatusOK {
+			t.Errorf("status = %d, want 200", status)
+		}
+		if n := hits.Load(); n != int32(DefaultMaxAttempts) {
+			t.Errorf("hits = %d, want %d", n, DefaultMaxAttempts)
+		}
+	})
+
+	t.Run("404 is not retried", func(t *testing.T) {
+		var hits atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer srv.Close()
+
+		_, status, err := client.get(context.Background(), srv.URL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if status != http.StatusNotFound {
+			t.Errorf("status = %d, want 404", status)
+		}
+		if n := hits.Load(); n != 1 {
+			t.Errorf("hits = %d, want 1 (404 must not retry)", n)
+		}
+	})
+
+	t.Run("exhausts retries on persistent 503", func(t *testing.T) {
+		var hits atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		_, _, err := client.get(context.Background(), srv.URL, nil)
+		if err == nil {
+			t.Fatal("expected error after exhausting retries")
+		}
+		if n := hits.Load(); n != int32(DefaultMaxAttempts) {
+			t.Errorf("hits = %d, want %d", n, DefaultMaxAttempts)
+		}
+	})
+
+	t.Run("respects cancelled context", func(t *testing.T) {
+		var hits atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel() // 立即取消
+
+		_, _, err := client.get(ctx, srv.URL, nil)
+		if err == nil {
+			t.Fatal("expected error for cancelled context")
+		}
+	})
+
+	t.Run("sends provided headers", func(t *testing.T) {
+		var gotAuth string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotAuth = r.Header.Get("Authorization")
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		_, _, err := client.get(context.Background(), srv.URL, map[string]string{"Authorization": "bearer xyz"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotAuth != "bearer xyz" {
+			t.Errorf("Authorization header = %q, want %q", gotAuth, "bearer xyz")
+		}
+	})
+}
+
+func TestOnRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	var attempts, failed atomic.Int32
+	client := NewClient("")
+	client.RetryBaseDelay = time.Millisecond
+	client.OnRequest = func(host string, duration time.Duration, isFailed bool) {
+		attempts.Add(1)
+		if isFailed {
+			failed.Add(1)
+		}
+	}
+	client.get(context.Background(), srv.URL, nil)
+	if attempts.Load() != DefaultMaxAttempts || failed.Load() != DefaultMaxAttempts {
+		t.Errorf("attempts = %d, failed = %d, want %d each", attempts.Load(), failed.Load(), DefaultMaxAttempts)
+	}
+}
+
+func TestRequestLog(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	out := bytes.Buffer{}
+	client := NewClient("")
+	client.RetryBaseDelay = time.Millisecond
+	client.Logger = slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client.get(context.Background(), srv.URL+"/path", nil)
+
+	type entry struct {
+		Level    string `json:"level"`
+		URL      string `json:"url"`
+		Status   int    `json:"status"`
+		Attempt  int    `json:"attempt"`
+		Duration int64  `json:"duration"`
+		Error    string `json:"error"`
+	}
+	entries := []entry{}
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var value entry
+		if err := decoder.Decode(&value); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, value)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if entries[0].Level != "DEBUG" || entries[0].URL != srv.URL+"/path" || entries[0].Status != 503 || entries[0].Attempt != 1 || entries[0].Error == "" {
+		t.Errorf("first attempt = %+v", entries[0])
+	}
+	if entries[1].Status != 200 || entries[1].Attempt != 2 || entries[1].Error != "" || entries[1].Duration <= 0 {
+		t.Errorf("second attempt = %+v", entries[1])
+	}
+}
+
+func TestConcurrentMap(t *testing.T) {
+	t.Run("preserves order despite out-of-order completion", func(t *testing.T) {
+		items := []int{0, 1, 2, 3, 4, 5, 6, 7}
+		got, err := concurrentMap(context.Background(), items, 4, func(ctx context.Context, n int) (int, error) {
+			// 越靠前的元素睡得越久 -> 越晚完成，制造乱序完成
+			time.Sleep(time.Duration(10*(len(items)-n)) * time.Millisecond)
+			return n * 10, nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []int{0, 10, 20, 30, 40, 50, 60, 70}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("returns the first error", func(t *testing.T) {
+		sentinel := errors.New("boom")
+		items := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		_, err := concurrentMap(context.Background(), items, 2, func(ctx context.Context, n int) (int, error) {
+			if n == 1 {
+				return 0, sentinel
+			}
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(50 * time.Millisecond):
+				return n, nil
+			}
+		})
+		if !errors.Is(err, sentinel) {
+			t.Fatalf("err = %v, want sentinel %v", err, sentinel)
+		}
+	})
+
+	t.Run("respects concurrency limit", func(t *testing.T) {
+		const limit = 3
+		var current, max atomic.Int32
+		items := make([]int, 30)
+		_, err := concurrentMap(context.Background(), items, limit, func(ctx context.Context, n int) (int, error) {
+			c := current.Add(1)
+			for { // 记录观察到的并发峰值
+				m := max.Load()
+				if c <= m || max.CompareAndSwap(m, c) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			current.Add(-1)
+			return 0, nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if m := max.Load(); m > limit {
+			t.Errorf("max observed concurrency = %d, want <= %d", m, limit)
+		}
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		got, err := concurrentMap(context.Background(), []int{}, 4, func(ctx context.Context, n int) (int, error) {
+			return n, nil
+		})
+		if err != nil || len(got) != 0 {
+			t.Errorf("got %v, err %v", got, err)
+		}
+	})
+}
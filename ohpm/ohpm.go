@@ -0,0 +1,308 @@
+// Package ohpm 是 ohpm.openharmony.cn 与 GitHub 的 API 客户端，
+// 抓取 package、publisher 以及 package 对应 Github 仓库的信息。
+//
+// 使用:
+//
+//	client := ohpm.NewClient(githubToken)
+//	names, err := client.MergePackageList(ctx, "6542179b6dad4e55f6635764", "@candies/extended_text", "", 0)
+//	packageInfoList, err := client.FetchPackages(ctx, names)
+package ohpm
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBaseURL 是 ohpm.openharmony.cn 的 API 地址
+	DefaultBaseURL = "https://ohpm.openharmony.cn"
+	// DefaultWebURL 是 ohpm.openharmony.cn 的网页地址（package / publisher 链接）
+	DefaultWebURL = "https://ohpm.openharmony.cn"
+	// DefaultGithubAPIURL 是 GitHub 的 API 地址
+	DefaultGithubAPIURL = "https://api.github.com"
+	// DefaultGithubWebURL 是 GitHub 的网页地址（识别仓库链接、生成链接）
+	DefaultGithubWebURL = "https://github.com"
+	// DefaultConcurrency 是 package 级别的并发抓取上限。
+	// 需要按 ohpm.openharmony.cn 和 GitHub 限流取保守值。
+	DefaultConcurrency = 8
+	// DefaultMaxAttempts 是单个 HTTP 请求的最大尝试次数（含首次）。
+	DefaultMaxAttempts = 3
+	// DefaultTimeout 是单个 HTTP 请求的超时时间，防止请求挂起拖跨整个 Action。
+	DefaultTimeout = 30 * time.Second
+	// DefaultRetryBaseDelay 是重试的基础退避时长（指数增长）。
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+)
+
+// Client 持有 API 地址、共享 HTTP Client、Github Token 与抓取选项，
+// 使用 [NewClient] 创建，字段可在首次请求前修改。
+type Client struct {
+	HTTPClient     *http.Client
+	BaseURL        string        // ohpm API 地址，默认 [DefaultBaseURL]
+	WebURL         string        // ohpm 网页地址，默认 [DefaultWebURL]
+	Authorization  string        // ohpm API 的 Authorization 请求头（私有 registry），为空时不发送
+	Registries     []Registry    // 其他具名 registry，package / publisher 以 "名称=" 前缀指定
+	GithubAPIURL   string        // GitHub API 地址，默认 [DefaultGithubAPIURL]
+	GithubWebURL   string        // GitHub 网页地址，默认 [DefaultGithubWebURL]
+	GithubToken    string        // 拥有 repo 权限的 Github 令牌，为空时匿名访问
+	GithubHosts    []GithubHost  // 其他 Github 主机（GitHub Enterprise Server），按仓库链接的域名匹配
+	Concurrency    int           // package 并发抓取上限
+	MaxAttempts    int           // 单个 HTTP 请求的最大尝试次数
+	RetryBaseDelay time.Duration // 重试的基础退避时长
+	// OnRequest 在每次 HTTP 尝试（含重试）后调用，用于统计指标（可为 nil）
+	OnRequest func(host string, duration time.Duration, failed bool)
+	// Logger 记录抓取进度（Info）与每次 HTTP 尝试（Debug），为 nil 时使用 [slog.Default]
+	Logger *slog.Logger
+
+	githubQuotaMutex sync.Mutex
+	githubQuotas     map[string]githubQuota   // 匿名访问时各 Github 主机（API 地址）的剩余额度
+	githubQuotaFetch map[string]chan struct{} // 正在获取额度的主机，获取完成时关闭
+}
+
+// 创建使用默认选项的 Client
+//
+// 复用同一个 HTTP Client 可共享连接池；
+// Timeout 防止单个请求挂起拖垮整个流程。
+//
+// 参数:
+//   - [githubToken] Github Token，为空时匿名访问 GitHub（额度耗尽后跳过 Github 信息）
+func NewClient(githubToken string) *Client {
+	return &Client{
+		HTTPClient:     &http.Client{Timeout: DefaultTimeout},
+		BaseURL:        DefaultBaseURL,
+		WebURL:         DefaultWebURL,
+		GithubAPIURL:   DefaultGithubAPIURL,
+		GithubWebURL:   DefaultGithubWebURL,
+		GithubToken:    githubToken,
+		Concurrency:    DefaultConcurrency,
+		MaxAttempts:    DefaultMaxAttempts,
+		RetryBaseDelay: DefaultRetryBaseDelay,
+	}
+}
+
+// 获取日志记录器
+func (c *Client) logger() *slog.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return slog.Default()
+}
+
+// ohpm 兼容的 registry（私有部署或镜像）
+type Registry struct {
+	Name          string `json:"name"`          // 名称，如 "internal"
+	BaseURL       string `json:"baseURL"`       // API 地址
+	WebURL        string `json:"webURL"`        // 网页地址，为空时同 BaseURL
+	Authorization string `json:"authorization"` // Authorization 请求头，为空时不发送
+}
+
+// 获取 registry 的请求头
+func (r Registry) headers() map[string]string {
+	if r.Authorization == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": r.Authorization}
+}
+
+// 按名称获取 registry
+//
+// 参数:
+//   - [name] registry 名称（为空时为默认 registry，即 [Client.BaseURL]）
+func (c *Client) registry(name string) (Registry, error) {
+	if name == "" {
+		return Registry{BaseURL: c.BaseURL, WebURL: c.WebURL, Authorization: c.Authorization}, nil
+	}
+	for _, value := range c.Registries {
+		if value.Name == name {
+			if value.WebURL == "" {
+				value.WebURL = value.BaseURL
+			}
+			return value, nil
+		}
+	}
+	return Registry{}, fmt.Errorf("🌏❌ Registry: unknown registry %q", name)
+}
+
+// 拆分 "registry=值" 形式的 package 名称或 publisher ID
+//
+// 参数:
+//   - [value] 如 "internal=@corp/utils"，无前缀时属于默认 registry
+//
+// 返回值:
+//   - registry 名称（默认 registry 为空）
+//   - package 名称或 publisher ID
+func SplitRegistryPrefix(value string) (string, string) {
+	if i := strings.Index(value, "="); i >= 0 {
+		return strings.TrimSpace(value[:i]), strings.TrimSpace(value[i+1:])
+	}
+	return "", value
+}
+
+// 为 package 名称或 publisher ID 加上 "registry=" 前缀，[SplitRegistryPrefix] 的逆操作
+//
+// 参数:
+//   - [registry] registry 名称（默认 registry 为空，不加前缀）
+//   - [value]    package 名称或 publisher ID
+func WithRegistryPrefix(registry string, value string) string {
+	if registry == "" {
+		return value
+	}
+	return registry + "=" + value
+}
+
+// 网页地址，为空时（如旧快照）为 [DefaultWebURL]
+func webURLOrDefault(webURL string) string {
+	if webURL == "" {
+		return DefaultWebURL
+	}
+	return strings.TrimSuffix(webURL, "/")
+}
+
+// 主 Package 信息，聚合 package 所有相关的数据
+type PackageInfo struct {
+	Code                   int // 0: error 1：success
+	Name                   string
+	Version                string
+	LicenseName            string
+	Description            string
+	Keywords               []string
+	Author                 string
+	PublisherId            string
+	Homepage               string
+	Repository             string
+	PublishTime            int
+	Points                 int
+	MaxPoints              int
+	PointItems             []PackagePointItem // points 各评分项明细
+	Likes                  int
+	Popularity             int
+	Downloads              int
+	GithubUser             string
+	GithubRepo             string
+	GithubBaseInfo         GithubBaseInfo
+	GithubContributorsInfo []GithubContributorsInfo
+	GithubLatestVersion    string            // Github 最新 Release（无 Release 时为最新 Tag）的 tag 名
+	Versions               []PackageVersion  // ohpm 版本历史（按发布时间倒序）
+	Dependencies           map[string]string // oh-package.json5 中声明的 dependencies（名称 -> 版本范围）
+	Dependents             int               // 依赖此 package 的 package 数量
+	HealthScore            int               // 健康度 0~100，由 dashboard.ApplyHealthScores 计算
+	HealthReasons          []string          // 拉低健康度的原因
+	LicenseIssues          []LicenseIssue    // license 检查问题，由 dashboard.ApplyLicenseChecks 检查
+	Registry               string            // registry 名称（默认 registry 为空）
+	RegistryWebURL         string            // registry 网页地址
+	GithubWebURL           string            // Github 仓库所在主机的网页地址
+	GithubSkipped          bool              // 匿名访问额度耗尽，未获取 Github 信息（仅链接）
+}
+
+// registry 网页地址（旧快照中为空时为 [DefaultWebURL]）
+func (p PackageInfo) RegistryURL() string {
+	return webURLOrDefault(p.RegistryWebURL)
+}
+
+// package 详情页链接
+func (p PackageInfo) DetailURL() string {
+	return p.RegistryURL() + "/#/cn/detail/" + url.PathEscape(p.Name)
+}
+
+// Github 仓库链接（旧快照中为空时为 github.com 上的仓库）
+func (p PackageInfo) GithubRepoURL() string {
+	webURL := p.GithubWebURL
+	if webURL == "" {
+		webURL = DefaultGithubWebURL
+	}
+	return strings.TrimSuffix(webURL, "/") + "/" + p.GithubUser + "/" + p.GithubRepo
+}
+
+// Github 仓库是否位于 github.com（shields.io 的 GitHub 徽章与固定头像地址仅支持 github.com）
+func (p PackageInfo) IsGithubCom() bool {
+	return p.GithubWebURL == "" || strings.TrimSuffix(p.GithubWebURL, "/") == DefaultGithubWebURL
+}
+
+// 每个 package 在 ohpm.openharmony.cn 的单个版本信息
+type PackageVersion struct {
+	Version     string
+	PublishTime int
+	Deprecated  bool
+}
+
+// ohpm.openharmony.cn points 的单个评分项
+type PackagePointItem struct {
+	Name    string `json:"name"`
+	Score   int    `json:"score"`   // 得分
+	Point   int    `json:"point"`   // 满分（与 pointDetail.point 一致，point 表示满分）
+	Message string `json:"message"` // 未得满分的原因
+}
+
+// 每个 package 对应 Github 仓库的基础信息
+type GithubBaseInfo struct {
+	StargazersCount int  `json:"stargazers_count"`
+	ForksCount      int  `json:"forks_count"`
+	OpenIssuesCount int  `json:"open_issues_count"`
+	Archived        bool `json:"archived"`
+	License         struct {
+		Name   string `json:"name"`
+		SpdxId string `json:"spdx_id"`
+	} `json:"license"`
+	ContributorsTotal int
+	NotFound          bool // 仓库不存在（404）
+}
+
+// 每个 package 对应 Github 仓库的贡献者基础信息
+type GithubContributorsInfo struct {
+	Login     string `json:"login"`
+	Id        int    `json:"id"`
+	AvatarUrl string `json:"avatar_url"`
+	HtmlUrl   string `json:"html_url"`
+	Type      string `json:"type"`
+}
+
+// 单个 license 检查问题（Kind 取值见 dashboard.CheckLicense）
+type LicenseIssue struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// Publisher 信息（来自 ohpm.openharmony.cn publisher 接口）
+type PublisherProfile struct {
+	Id             string
+	Name           string
+	Avatar         string
+	PackageTotal   int
+	Registry       string // registry 名称（默认 registry 为空）
+	RegistryWebURL string // registry 网页地址
+}
+
+// publisher 主页链接
+func (p PublisherProfile) DetailURL() string {
+	return webURLOrDefault(p.RegistryWebURL) + "/#/cn/publisher/" + url.PathEscape(p.Id)
+}
+
+// 拆分逗号分割的列表，去重并去除首尾空白与空字符串
+//
+// 参数:
+//   - [list] 列表（逗号,分割），如 "@a/b, @a/c"
+//
+// 返回值:
+//   - 保持首次出现顺序的列表
+func SplitList(list string) []string {
+	return removeDuplicates(strings.Split(list, ","))
+}
+
+// 去重并保持首次出现的顺序，同时去除首尾空白与空字符串
+func removeDuplicates(arr []string) []string {
+	seen := make(map[string]bool, len(arr))
+	result := []string{}
+	for _, v := range arr {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package ohpm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemoveDuplicates(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{
+			name: "preserves first-seen order",
+			in:   []string{"b", "a", "b", "c", "a"},
+			want: []string{"b", "a", "c"},
+		},
+		{
+			name: "trims whitespace and drops empty",
+			in:   []string{"a", "", " b ", "a", "   ", "b"},
+			want: []string{"a", "b"},
+		},
+		{
+			name: "empty input",
+			in:   []string{},
+			want: []string{},
+		},
+		{
+			name: "all empty/blank",
+			in:   []string{"", "  ", "\t"},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := removeDuplicates(tt.in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("removeDuplicates(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryPrefix(t *testing.T) {
+	tests := []struct {
+		value    string
+		registry string
+		name     string
+	}{
+		{"@candies/extended_text", "", "@candies/extended_text"},
+		{"internal=@corp/utils", "internal", "@corp/utils"},
+		{" internal = 6542179b6dad4e55f6635764", "internal", "6542179b6dad4e55f6635764"},
+	}
+	for _, tt := range tests {
+		registry, name := SplitRegistryPrefix(tt.value)
+		if registry != tt.registry || name != tt.name {
+			t.Errorf("SplitRegistryPrefix(%q) = %q, %q, want %q, %q", tt.value, registry, name, tt.registry, tt.name)
+		}
+	}
+	if got := WithRegistryPrefix("internal", "@corp/utils"); got != "internal=@corp/utils" {
+		t.Errorf("got %q", got)
+	}
+	if got := WithRegistryPrefix("", "@corp/utils"); got != "@corp/utils" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestDetailURL(t *testing.T) {
+	// 旧快照无 RegistryWebURL，回退到 ohpm.openharmony.cn
+	if got, want := (PackageInfo{Name: "@a/b"}).DetailURL(), "https://ohpm.openharmony.cn/#/cn/detail/@a%2Fb"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := (PackageInfo{Name: "@a/b", RegistryWebURL: "https://ohpm.example.com/"}).DetailURL(), "https://ohpm.example.com/#/cn/detail/@a%2Fb"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,545 @@
+package ohpm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ohpm.openharmony.cn openapi 路径（位于 [Client.BaseURL] / [Registry.BaseURL] 之后）
+const openAPIPath = "/ohpmweb/registry/oh-package/openapi/v1"
+
+// ohpm.openharmony.cn package 基础信息（接口响应 body 字段的内容）
+type packageBaseInfo struct {
+	Name        string        `json:"name"`
+	Version     string        `json:"version"`
+	Description string        `json:"description"`
+	Keywords    []string      `json:"keywords"`
+	Author      packageAuthor `json:"author"`
+	PublisherId string        `json:"publisherId"`
+	License     string        `json:"license"`
+	Homepage    string        `json:"homepage"`
+	Repository  string        `json:"repository"`
+	PublishTime int           `json:"publishTime"`
+	Points      int           `json:"points"`
+	Likes       int           `json:"likes"`
+	Popularity  int           `json:"popularity"`
+	Downloads   int           `json:"downloads"`
+	// oh-package.json5 中声明的 dependencies
+	Dependencies map[string]string `json:"dependencies"`
+	// 被依赖数量
+	DependentsCount int `json:"dependentsCount"`
+	PointDetail     struct {
+		Point int                `json:"point"`
+		Items []PackagePointItem `json:"items"`
+	} `json:"pointDetail"`
+}
+
+// ohpm.openharmony.cn package 描述信息（接口响应 body 字段的内容）
+type packageDescriptionInfo struct {
+	Rows []packageDescriptionRow `json:"rows"`
+}
+
+// ohpm.openharmony.cn search 接口返回的单个 package 描述信息
+type packageDescriptionRow struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Keywords    []string      `json:"keywords"`
+	Homepage    string        `json:"homepage"`
+	Author      packageAuthor `json:"author"`
+}
+
+// packageAuthor 兼容字符串与 {"name": ...} 对象两种形式的 author 字段，
+// 其他形式降级为空，避免单个字段导致整个 package 解析失败。
+type packageAuthor string
+
+func (a *packageAuthor) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*a = packageAuthor(name)
+		return nil
+	}
+	var author struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &author); err != nil {
+		*a = ""
+		return nil
+	}
+	*a = packageAuthor(author.Name)
+	return nil
+}
+
+// ohpm.openharmony.cn package 版本列表（接口响应 body 字段的内容）
+type packageVersionsInfo struct {
+	Rows []struct {
+		Version     string       `json:"version"`
+		PublishTime int          `json:"publishTime"`
+		Deprecated  flexibleBool `json:"deprecated"`
+	} `json:"rows"`
+}
+
+// flexibleBool 兼容布尔值与字符串两种形式的 JSON 字段，
+// 如 deprecated 可能为 true/false，也可能为废弃说明（非空字符串即为 true）。
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case bool:
+		*b = flexibleBool(v)
+	case string:
+		*b = flexibleBool(strings.TrimSpace(v) != "" && v != "false")
+	default:
+		*b = false
+	}
+	return nil
+}
+
+// ohpm.openharmony.cn publisher 基础信息（接口响应 body 字段的内容）
+type publisherProfileInfo struct {
+	Name         string `json:"name"`
+	DisplayName  string `json:"displayName"`
+	Avatar       string `json:"avatar"`
+	PackageCount int    `json:"packageCount"`
+}
+
+// ohpm.openharmony.cn search 接口的 package 列表，用于 publisher 与搜索条件查询（接口响应 body 字段的内容）
+type publisherInfo struct {
+	Rows []struct {
+		Name string `json:"name"`
+	} `json:"rows"`
+}
+
+// 合并 publisher 的 package、搜索结果和自定义 package 列表，并去重（保持顺序）
+//
+// 参数:
+//   - [ctx]           上下文
+//   - [publisherList] publisher ID 列表（逗号,分割），可加 "registry=" 前缀
+//   - [packageList]   package 名称列表（逗号,分割），可加 "registry=" 前缀
+//   - [searchQuery]   ohpm 搜索条件（为空时不搜索，仅搜索默认 registry）
+//   - [searchMax]     搜索结果最大数量
+//
+// 返回值:
+//   - 合并去重后的 package 名称列表（非默认 registry 的 package 带 "registry=" 前缀）
+func (c *Client) MergePackageList(ctx context.Context, publisherList string, packageList string, searchQuery string, searchMax int) ([]string, error) {
+	publisherPackages, err := c.PublisherPackages(ctx, publisherList)
+	if err != nil {
+		return nil, err
+	}
+	searchPackages, err := c.SearchPackages(ctx, searchQuery, searchMax)
+	if err != nil {
+		return nil, err
+	}
+	all := append(publisherPackages, searchPackages...)
+	all = append(all, strings.Split(packageList, ",")...)
+	return removeDuplicates(all), nil
+}
+
+// 通过 Publisher 获取所有 Package 名称
+//
+// 参数:
+//   - [ctx]         上下文
+//   - [publisherId] publisher ID 列表（逗号,分割），可加 "registry=" 前缀
+//
+// 返回值:
+//   - package 名称列表（带与 publisher 相同的 "registry=" 前缀）
+func (c *Client) PublisherPackages(ctx context.Context, publisherId string) ([]string, error) {
+	printErrTitle := "🌏⚠️ PublisherPackages: "
+	if strings.TrimSpace(publisherId) == "" {
+		return nil, nil
+	}
+	publisherList := SplitList(publisherId)
+	c.logger().Info("🌏 PublisherPackages", "publishers", publisherList)
+	packageNameList := []string{}
+	for _, publisher := range publisherList {
+		registryName, id := SplitRegistryPrefix(publisher)
+		registry, err := c.registry(registryName)
+		if err != nil {
+			return nil, err
+		}
+		query := "publisherId=" + url.QueryEscape(id) + "&sortedType=latest&isHomePage=false&condition="
+		names, err := c.searchPackageNames(ctx, registry, "Publisher: "+publisher, query, 0, printErrTitle)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			packageNameList = append(packageNameList, WithRegistryPrefix(registryName, name))
+		}
+	}
+	return removeDuplicates(packageNameList), nil
+}
+
+// 获取 Publisher 基础信息
+//
+// 参数:
+//   - [ctx]         上下文
+//   - [publisherId] publisher ID 列表（逗号,分割），可加 "registry=" 前缀
+//
+// 返回值:
+//   - [PublisherProfile] 列表（与去重后的 publisher ID 顺序一致，404 时仅保留 ID）
+func (c *Client) PublisherProfiles(ctx context.Context, publisherId string) ([]PublisherProfile, error) {
+	printErrTitle := "🌏⚠️ PublisherProfile: "
+	publisherProfiles := []PublisherProfile{}
+	for _, publisher := range SplitList(publisherId) {
+		registryName, id := SplitRegistryPrefix(publisher)
+		registry, err := c.registry(registryName)
+		if err != nil {
+			return nil, err
+		}
+		profile := PublisherProfile{Id: id, Registry: registryName, RegistryWebURL: registry.WebURL}
+		rawURL := fmt.Sprintf("%s%s/publisher/%s", registry.BaseURL, openAPIPath, url.PathEscape(id))
+		body, status, err := c.get(ctx, rawURL, registry.headers())
+		if err != nil {
+			return nil, fmt.Errorf("%s%w", printErrTitle, err)
+		}
+		if status != http.StatusOK && status != http.StatusNotFound {
+			return nil, fmt.Errorf("%s%s: unexpected status %d", printErrTitle, publisher, status)
+		}
+		if status == http.StatusOK {
+			data, ok, err := decodeBody[publisherProfileInfo](body)
+			if err != nil {
+				return nil, fmt.Errorf("%s%w", printErrTitle, err)
+			}
+			if ok {
+				profile.Name = data.DisplayName
+				if profile.Name == "" {
+					profile.Name = data.Name
+				}
+				profile.Avatar = data.Avatar
+				profile.PackageTotal = data.PackageCount
+			}
+		}
+		publisherProfiles = append(publisherProfiles, profile)
+	}
+	return publisherProfiles, nil
+}
+
+// 通过搜索条件获取 Package 名称（默认 registry）
+//
+// 参数:
+//   - [ctx]         上下文
+//   - [searchQuery] ohpm 搜索条件，如 "keyword:lottie" 或任意文本
+//   - [searchMax]   最大数量（<= 0 时不限制）
+//
+// 返回值:
+//   - package 名称列表（按相关度排序）
+func (c *Client) SearchPackages(ctx context.Context, searchQuery string, searchMax int) ([]string, error) {
+	printErrTitle := "🔍⚠️ SearchPackages: "
+	searchQuery = strings.TrimSpace(searchQuery)
+	if searchQuery == "" {
+		return nil, nil
+	}
+	c.logger().Info("🔍 SearchPackages", "query", searchQuery)
+	registry, err := c.registry("")
+	if err != nil {
+		return nil, err
+	}
+	query := "condition=" + url.QueryEscape(searchQuery) + "&sortedType=relevancy&isHomePage=false"
+	names, err := c.searchPackageNames(ctx, registry, "Search: "+searchQuery, query, searchMax, printErrTitle)
+	if err != nil {
+		return nil, err
+	}
+	return removeDuplicates(names), nil
+}
+
+// 搜索接口每页数量
+const searchPageSize = 10
+
+// 逐页查询 ohpm search 接口，直至返回空结果、不足一页或达到数量上限
+//
+// 参数:
+//   - [ctx]           上下文
+//   - [registry]      registry
+//   - [label]         日志标签
+//   - [query]         除分页参数外的查询参数（已编码）
+//   - [limit]         最大数量（<= 0 时不限制）
+//   - [printErrTitle] 错误标题
+//
+// 返回值:
+//   - package 名称列表
+func (c *Client) searchPackageNames(ctx context.Context, registry Registry, label string, query string, limit int, printErrTitle string) ([]string, error) {
+	packageNameList := []string{}
+	for pageIndex := 1; ; pageIndex++ {
+		c.logger().Info("🌏🔗 Search page", "label", label, "page", pageIndex)
+		rawURL := fmt.Sprintf("%s%s/search?pageNum=%d&pageSize=%d&%s", registry.BaseURL, openAPIPath, pageIndex, searchPageSize, query)
+		body, status, err := c.get(ctx, rawURL, registry.headers())
+		if err != nil {
+			return nil, fmt.Errorf("%s%w", printErrTitle, err)
+		}
+		if status == http.StatusNotFound {
+			break // 无更多结果
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("%s%s: unexpected status %d", printErrTitle, label, status)
+		}
+		data, ok, err := decodeBody[publisherInfo](body)
+		if err != nil {
+			return nil, fmt.Errorf("%s%w", printErrTitle, err)
+		}
+		// body 非对象或无结果 -> 结束分页
+		if !ok || len(data.Rows) == 0 {
+			break
+		}
+		for _, row := range data.Rows {
+			if row.Name != "" {
+				packageNameList = append(packageNameList, row.Name)
+			}
+			if limit > 0 && len(packageNameList) >= limit {
+				return packageNameList, nil
+			}
+		}
+		// 不足一页 -> 最后一页
+		if len(data.Rows) < searchPageSize {
+			break
+		}
+	}
+	return packageNameList, nil
+}
+
+// 获取所有 Package 信息（并发抓取）
+//
+// 以 [Client.Concurrency] 为上限并发处理每个 package，结果按输入顺序返回，保证排序前顺序确定。
+// 任一 package 抓取失败将取消其余请求并整体返回错误。
+//
+// 参数:
+//   - [ctx]          上下文
+//   - [packageNames] package 名称列表（已去重清洗）
+//
+// 返回值:
+//   - [PackageInfo] 列表（与 packageNames 顺序一致）
+func (c *Client) FetchPackages(ctx context.Context, packageNames []string) ([]PackageInfo, error) {
+	c.logger().Info("📦 FetchPackages", "packages", packageNames)
+	return concurrentMap(ctx, packageNames, c.Concurrency, func(ctx context.Context, name string) (PackageInfo, error) {
+		c.logger().Info("📦🔥 Fetching package", "package", name)
+		info, err := c.FetchPackage(ctx, name)
+		if err != nil {
+			return PackageInfo{}, err
+		}
+		if info.Code == 1 {
+			c.logger().Info("📦✅ Package found", "package", name, "code", 1)
+		} else {
+			c.logger().Warn("📦❌ Package not found", "package", name, "code", 0)
+		}
+		return info, nil
+	})
+}
+
+// 抓取单个 package 的全部信息（ohpm 基础信息 -> 描述 -> 版本历史 -> Github 信息）
+//
+// 参数:
+//   - [ctx]  上下文
+//   - [name] package 名称，可加 "registry=" 前缀
+//
+// 返回值:
+//   - [PackageInfo]，包不存在时 Code=0（降级展示为 ⁉️，非错误）
+func (c *Client) FetchPackage(ctx context.Context, name string) (PackageInfo, error) {
+	printErrTitle := "📦⚠️ PackageInfo: "
+	registryName, name := SplitRegistryPrefix(name)
+	registry, err := c.registry(registryName)
+	if err != nil {
+		return PackageInfo{}, err
+	}
+	notFound := PackageInfo{Code: 0, Name: name, Registry: registryName, RegistryWebURL: registry.WebURL}
+	rawURL := fmt.Sprintf("%s%s/detail/%s", registry.BaseURL, openAPIPath, url.PathEscape(name))
+	body, status, err := c.get(ctx, rawURL, registry.headers())
+	if err != nil {
+		return PackageInfo{}, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	// 404：包不存在 -> 降级
+	if status == http.StatusNotFound {
+		return notFound, nil
+	}
+	if status != http.StatusOK {
+		return PackageInfo{}, fmt.Errorf("%s%s: unexpected status %d", printErrTitle, name, status)
+	}
+	data, ok, err := decodeBody[packageBaseInfo](body)
+	if err != nil {
+		return PackageInfo{}, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	// 不存在的 package 接口仍返回 200，但 body 为 "success" 字符串（非对象）-> 降级，
+	// body 为对象但缺少 name 同样降级。
+	if !ok || data.Name == "" {
+		return notFound, nil
+	}
+
+	packageInfo := PackageInfo{
+		Code:           1,
+		Name:           data.Name,
+		Version:        data.Version,
+		LicenseName:    data.License,
+		Homepage:       data.Homepage,
+		Repository:     data.Repository,
+		PublishTime:    data.PublishTime,
+		Points:         data.Points,
+		MaxPoints:      data.PointDetail.Point,
+		PointItems:     data.PointDetail.Items,
+		Likes:          data.Likes,
+		Popularity:     data.Popularity,
+		Downloads:      data.Downloads,
+		PublisherId:    data.PublisherId,
+		Dependencies:   data.Dependencies,
+		Dependents:     data.DependentsCount,
+		Registry:       registryName,
+		RegistryWebURL: registry.WebURL,
+	}
+
+	packageInfo.Description = data.Description
+	packageInfo.Keywords = data.Keywords
+	packageInfo.Author = string(data.Author)
+	// 详情接口缺少描述时，回退到名称完全匹配的搜索结果
+	if packageInfo.Description == "" {
+		description, err := c.packageDescription(ctx, registry, data.Name)
+		if err != nil {
+			return PackageInfo{}, err
+		}
+		packageInfo.Description = description.Description
+		if len(packageInfo.Keywords) == 0 {
+			packageInfo.Keywords = description.Keywords
+		}
+		if packageInfo.Author == "" {
+			packageInfo.Author = string(description.Author)
+		}
+		if packageInfo.Homepage == "" {
+			packageInfo.Homepage = description.Homepage
+		}
+	}
+
+	versions, err := c.packageVersions(ctx, registry, data.Name)
+	if err != nil {
+		return PackageInfo{}, err
+	}
+	packageInfo.Versions = versions
+
+	if err := c.githubInfo(ctx, &packageInfo); err != nil {
+		return PackageInfo{}, err
+	}
+	return packageInfo, nil
+}
+
+// 获取 Package 描述信息
+//
+// 搜索结果按相关度排序，名称互为前缀时首条可能是其他 package，
+// 因此只取名称完全匹配的结果。
+//
+// 参数:
+//   - [ctx]         上下文
+//   - [registry]    registry
+//   - [packageName] 单个 package 名称
+//
+// 返回值:
+//   - [packageDescriptionRow] 描述信息（404 或无完全匹配时降级为空）
+func (c *Client) packageDescription(ctx context.Context, registry Registry, packageName string) (packageDescriptionRow, error) {
+	printErrTitle := "📦⚠️ PackageDescriptionInfo: "
+	rawURL := fmt.Sprintf("%s%s/search?condition=name:%s&pageNum=1&pageSize=10&sortedType=relevancy&isHomePage=false", registry.BaseURL, openAPIPath, url.QueryEscape(packageName))
+	body, status, err := c.get(ctx, rawURL, registry.headers())
+	if err != nil {
+		return packageDescriptionRow{}, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	if status == http.StatusNotFound {
+		return packageDescriptionRow{}, nil // 无描述数据 -> 降级
+	}
+	if status != http.StatusOK {
+		return packageDescriptionRow{}, fmt.Errorf("%s%s: unexpected status %d", printErrTitle, packageName, status)
+	}
+	data, ok, err := decodeBody[packageDescriptionInfo](body)
+	if err != nil {
+		return packageDescriptionRow{}, fmt.Errorf("%s%w", printErrTitle, err)
+	}
+	if !ok {
+		return packageDescriptionRow{}, nil
+	}
+	row, _ := matchPackageDescriptionRow(data.Rows, packageName)
+	return row, nil
+}
+
+// 从搜索结果中找出名称完全匹配的 package
+//
+// 参数:
+//   - [rows]        搜索结果
+//   - [packageName] package 名称
+//
+// 返回值:
+//   - 匹配的描述信息
+//   - 是否匹配
+func matchPackageDescriptionRow(rows []packageDescriptionRow, packageName string) (packageDescriptionRow, bool) {
+	for _, row := range rows {
+		if row.Name == packageName {
+			return row, true
+		}
+	}
+	return packageDescriptionRow{}, false
+}
+
+// 获取 Package 版本历史
+//
+// 逐页查询，直至返回空结果或不足一页。
+//
+// 参数:
+//   - [ctx]         上下文
+//   - [packageName] 单个 package 名称，可加 "registry=" 前缀
+//
+// 返回值:
+//   - [PackageVersion] 列表（按发布时间倒序，404 时降级为空）
+func (c *Client) PackageVersions(ctx context.Context, packageName string) ([]PackageVersion, error) {
+	registryName, packageName := SplitRegistryPrefix(packageName)
+	registry, err := c.registry(registryName)
+	if err != nil {
+		return nil, err
+	}
+	return c.packageVersions(ctx, registry, packageName)
+}
+
+// 版本历史每页数量
+const versionsPageSize = 100
+
+// 获取指定 registry 中 Package 的版本历史，见 [Client.PackageVersions]
+func (c *Client) packageVersions(ctx context.Context, registry Registry, packageName string) ([]PackageVersion, error) {
+	printErrTitle := "📦⚠️ PackageVersions: "
+	versions := []PackageVersion{}
+	for pageIndex := 1; ; pageIndex++ {
+		rawURL := fmt.Sprintf("%s%s/versions/%s?pageNum=%d&pageSize=%d", registry.BaseURL, openAPIPath, url.PathEscape(packageName), pageIndex, versionsPageSize)
+		body, status, err := c.get(ctx, rawURL, registry.headers())
+		if err != nil {
+			return nil, fmt.Errorf("%s%w", printErrTitle, err)
+		}
+		if status == http.StatusNotFound {
+			break // 无版本数据 -> 降级
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("%s%s: unexpected status %d", printErrTitle, packageName, status)
+		}
+		data, ok, err := decodeBody[packageVersionsInfo](body)
+		if err != nil {
+			return nil, fmt.Errorf("%s%w", printErrTitle, err)
+		}
+		// body 非对象或无结果 -> 结束分页
+		if !ok || len(data.Rows) == 0 {
+			break
+		}
+		for _, row := range data.Rows {
+			if row.Version != "" {
+				versions = append(versions, PackageVersion{
+					Version:     row.Version,
+					PublishTime: row.PublishTime,
+					Deprecated:  bool(row.Deprecated),
+				})
+			}
+		}
+		// 不足一页 -> 最后一页（同时避免忽略 pageNum 的服务端无限分页）
+		if len(data.Rows) < versionsPageSize {
+			break
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].PublishTime > versions[j].PublishTime
+	})
+	return versions, nil
+}
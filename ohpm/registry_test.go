@@ -0,0 +1,380 @@
+package ohpm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDecodeBody(t *testing.T) {
+	t.Run("detail object decodes", func(t *testing.T) {
+		raw := []byte(`{"code":200,"body":{"name":"@candies/extended_text","version":"1.0.0","downloads":42}}`)
+		got, ok, err := decodeBody[packageBaseInfo](raw)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ok {
+			t.Fatal("ok = false, want true")
+		}
+		if got.Name != "@candies/extended_text" || got.Version != "1.0.0" || got.Downloads != 42 {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("detail description, keywords and author decode", func(t *testing.T) {
+		raw := []byte(`{"code":200,"body":{"name":"@a/b","description":"desc","keywords":["x","y"],"author":{"name":"amos"}}}`)
+		got, ok, err := decodeBody[packageBaseInfo](raw)
+		if err != nil || !ok {
+			t.Fatalf("ok=%v err=%v", ok, err)
+		}
+		if got.Description != "desc" || !reflect.DeepEqual(got.Keywords, []string{"x", "y"}) || got.Author != "amos" {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("point detail items decode", func(t *testing.T) {
+		raw := []byte(`{"code":200,"body":{"name":"@a/b","points":80,"pointDetail":{"point":100,"items":[{"name":"README","score":20,"point":20},{"name":"License","score":0,"point":20,"message":"missing"}]}}}`)
+		got, ok, err := decodeBody[packageBaseInfo](raw)
+		if err != nil || !ok {
+			t.Fatalf("ok=%v err=%v", ok, err)
+		}
+		want := []PackagePointItem{{Name: "README", Score: 20, Point: 20}, {Name: "License", Score: 0, Point: 20, Message: "missing"}}
+		if got.PointDetail.Point != 100 || !reflect.DeepEqual(got.PointDetail.Items, want) {
+			t.Errorf("got %+v", got.PointDetail)
+		}
+	})
+
+	t.Run("string author and unexpected author shape", func(t *testing.T) {
+		raw := []byte(`{"code":200,"body":{"rows":[{"name":"a","author":"amos"},{"name":"b","author":42}]}}`)
+		got, ok, err := decodeBody[packageDescriptionInfo](raw)
+		if err != nil || !ok {
+			t.Fatalf("ok=%v err=%v", ok, err)
+		}
+		if got.Rows[0].Author != "amos" || got.Rows[1].Author != "" {
+			t.Errorf("got %+v", got.Rows)
+		}
+	})
+
+	t.Run("non-existent package (body is success string) degrades", func(t *testing.T) {
+		// OHPM 对不存在的 package 仍返回 200，但 body 为字符串 "success"。
+		raw := []byte(`{"code":200,"body":"success"}`)
+		got, ok, err := decodeBody[packageBaseInfo](raw)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ok {
+			t.Error("ok = true, want false for string body")
+		}
+		if got.Name != "" {
+			t.Errorf("got.Name = %q, want empty", got.Name)
+		}
+	})
+
+	t.Run("null body decodes to zero value (ok=true)", func(t *testing.T) {
+		raw := []byte(`{"code":200,"body":null}`)
+		got, ok, err := decodeBody[packageBaseInfo](raw)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// null 可解析为零值对象；调用方再按 Name 为空降级。
+		if !ok {
+			t.Error("ok = false, want true for null body")
+		}
+		if got.Name != "" {
+			t.Errorf("got.Name = %q, want empty", got.Name)
+		}
+	})
+
+	t.Run("empty object body has empty name", func(t *testing.T) {
+		raw := []byte(`{"code":200,"body":{}}`)
+		got, ok, err := decodeBody[packageBaseInfo](raw)
+		if err != nil || !ok {
+			t.Fatalf("ok=%v err=%v", ok, err)
+		}
+		if got.Name != "" {
+			t.Errorf("got.Name = %q, want empty", got.Name)
+		}
+	})
+
+	t.Run("invalid json returns error", func(t *testing.T) {
+		raw := []byte(`not json`)
+		_, ok, err := decodeBody[packageBaseInfo](raw)
+		if err == nil {
+			t.Fatal("expected error for invalid json")
+		}
+		if ok {
+			t.Error("ok = true, want false on error")
+		}
+	})
+
+	t.Run("publisher rows decode", func(t *testing.T) {
+		raw := []byte(`{"code":200,"body":{"rows":[{"name":"a"},{"name":"b"}]}}`)
+		got, ok, err := decodeBody[publisherInfo](raw)
+		if err != nil || !ok {
+			t.Fatalf("ok=%v err=%v", ok, err)
+		}
+		if len(got.Rows) != 2 || got.Rows[0].Name != "a" || got.Rows[1].Name != "b" {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("publisher end-of-pages (success string) degrades", func(t *testing.T) {
+		raw := []byte(`{"code":200,"body":"success"}`)
+		got, ok, err := decodeBody[publisherInfo](raw)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ok {
+			t.Error("ok = true, want false for string body")
+		}
+		if len(got.Rows) != 0 {
+			t.Errorf("got %+v, want no rows", got)
+		}
+	})
+}
+
+func TestMatchPackageDescriptionRow(t *testing.T) {
+	rows := []packageDescriptionRow{
+		{Name: "@candies/extended_text_field", Description: "wrong"},
+		{Name: "@candies/extended_text", Description: "right"},
+	}
+
+	got, ok := matchPackageDescriptionRow(rows, "@candies/extended_text")
+	if !ok || got.Description != "right" {
+		t.Errorf("got (%+v, %v), want exact match", got, ok)
+	}
+
+	// 仅前缀匹配时不取首条
+	got, ok = matchPackageDescriptionRow(rows[:1], "@candies/extended_text")
+	if ok || got.Description != "" {
+		t.Errorf("got (%+v, %v), want no match", got, ok)
+	}
+}
+
+func TestDecodePackageVersions(t *testing.T) {
+	raw := []byte(`{"code":200,"body":{"rows":[{"version":"1.0.1","publishTime":200,"deprecated":"use 1.0.2"},{"version":"1.0.0","publishTime":100,"deprecated":false}]}}`)
+	got, ok, err := decodeBody[packageVersionsInfo](raw)
+	if err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if len(got.Rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(got.Rows))
+	}
+	if !got.Rows[0].Deprecated || got.Rows[1].Deprecated {
+		t.Errorf("deprecated = (%v, %v), want (true, false)", got.Rows[0].Deprecated, got.Rows[1].Deprecated)
+	}
+}
+
+func TestClientPackageVersionsPaging(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		// 第一页满页，第二页不足一页 -> 两次请求（不再请求空页）
+		rows := []string{}
+		count := versionsPageSize
+		if r.URL.Query().Get("pageNum") != "1" {
+			count = 2
+		}
+		for i := range count {
+			rows = append(rows, `{"version":"`+r.URL.Query().Get("pageNum")+`.`+strconv.Itoa(i)+`.0","publishTime":`+strconv.Itoa(i)+`}`)
+		}
+		w.Write([]byte(`{"code":200,"body":{"rows":[` + strings.Join(rows, ",") + `]}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("")
+	client.BaseURL = server.URL
+	versions, err := client.PackageVersions(context.Background(), "@a/b")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(versions) != versionsPageSize+2 || requests.Load() != 2 {
+		t.Errorf("got %d versions in %d requests", len(versions), requests.Load())
+	}
+
+	// 忽略 pageNum、始终返回同一页（不足一页）-> 一次请求
+	requests.Store(0)
+	ignoring := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"code":200,"body":{"rows":[{"version":"1.0.0","publishTime":1}]}}`))
+	}))
+	defer ignoring.Close()
+	client.BaseURL = ignoring.URL
+	versions, err = client.PackageVersions(context.Background(), "@a/b")
+	if err != nil || len(versions) != 1 || requests.Load() != 1 {
+		t.Errorf("got %v, %v in %d requests", versions, err, requests.Load())
+	}
+}
+
+func TestClientSearchPackagesPaging(t *testing.T) {
+	const total = 2*searchPageSize + 5
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		query := r.URL.Query()
+		if !strings.HasSuffix(r.URL.Path, "/search") || query.Get("condition") != "keyword:lottie" || query.Get("sortedType") != "relevancy" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		// 共 total 个结果，最后一页不足一页
+		pageNum, _ := strconv.Atoi(query.Get("pageNum"))
+		rows := []string{}
+		for i := (pageNum - 1) * searchPageSize; i < min(pageNum*searchPageSize, total); i++ {
+			rows = append(rows, `{"name":"@a/p`+strconv.Itoa(i)+`"}`)
+		}
+		w.Write([]byte(`{"code":200,"body":{"rows":[` + strings.Join(rows, ",") + `]}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("")
+	client.BaseURL = server.URL
+
+	tests := []struct {
+		name         string
+		searchMax    int
+		wantNames    int
+		wantRequests int32
+	}{
+		{"short page ends paging", 0, total, 3},
+		{"limit cuts off mid-page", searchPageSize + 5, searchPageSize + 5, 2},
+		{"limit at page end", searchPageSize, searchPageSize, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			names, err := client.SearchPackages(context.Background(), " keyword:lottie ", tt.searchMax)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(names) != tt.wantNames || requests.Load() != tt.wantRequests {
+				t.Errorf("got %d names in %d requests, want %d in %d", len(names), requests.Load(), tt.wantNames, tt.wantRequests)
+			}
+			if len(names) > 0 && (names[0] != "@a/p0" || names[len(names)-1] != "@a/p"+strconv.Itoa(tt.wantNames-1)) {
+				t.Errorf("names = %v", names)
+			}
+		})
+	}
+}
+
+func TestClientFetchPackage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ohpmweb/registry/oh-package/openapi/v1/detail/{name...}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("name") != "@a/b" {
+			w.Write([]byte(`{"code":200,"body":"success"}`))
+			return
+		}
+		w.Write([]byte(`{"code":200,"body":{"name":"@a/b","version":"1.0.0","repository":"https://github.com/u/r","pointDetail":{"point":100}}}`))
+	})
+	mux.HandleFunc("GET /ohpmweb/registry/oh-package/openapi/v1/search", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":200,"body":{"rows":[{"name":"@a/b","description":"desc"}]}}`))
+	})
+	mux.HandleFunc("GET /ohpmweb/registry/oh-package/openapi/v1/versions/{name...}", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pageNum") != "1" {
+			w.Write([]byte(`{"code":200,"body":{"rows":[]}}`))
+			return
+		}
+		w.Write([]byte(`{"code":200,"body":{"rows":[{"version":"1.0.0","publishTime":100}]}}`))
+	})
+	mux.HandleFunc("GET /repos/u/r", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"stargazers_count":7}`))
+	})
+	mux.HandleFunc("GET /repos/u/r/contributors", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"login":"a","id":1,"type":"User"},{"login":"bot","id":2,"type":"Bot"}]`))
+	})
+	mux.HandleFunc("GET /repos/u/r/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name":"v1.1.0"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := NewClient("token")
+	client.BaseURL = srv.URL
+	client.GithubAPIURL = srv.URL
+
+	list, err := client.FetchPackages(context.Background(), []string{"@a/b", "@a/missing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := list[0]
+	if got.Code != 1 || got.Description != "desc" || got.MaxPoints != 100 || len(got.Versions) != 1 {
+		t.Errorf("got %+v", got)
+	}
+	if got.GithubUser != "u" || got.GithubRepo != "r" || got.GithubBaseInfo.StargazersCount != 7 ||
+		got.GithubBaseInfo.ContributorsTotal != 2 || len(got.GithubContributorsInfo) != 1 || got.GithubLatestVersion != "v1.1.0" {
+		t.Errorf("github info = %+v", got)
+	}
+	if list[1].Code != 0 || list[1].Name != "@a/missing" {
+		t.Errorf("missing package = %+v", list[1])
+	}
+}
+
+func TestClientRegistries(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ohpmweb/registry/oh-package/openapi/v1/detail/{name...}", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer internal" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"code":200,"body":{"name":"` + r.PathValue("name") + `","version":"1.0.0","description":"desc","publisherId":"corp"}}`))
+	})
+	mux.HandleFunc("GET /ohpmweb/registry/oh-package/openapi/v1/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pageNum") != "1" {
+			w.Write([]byte(`{"code":200,"body":{"rows":[]}}`))
+			return
+		}
+		w.Write([]byte(`{"code":200,"body":{"rows":[{"name":"@corp/a"}]}}`))
+	})
+	mux.HandleFunc("GET /ohpmweb/registry/oh-package/openapi/v1/versions/{name...}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":200,"body":{"rows":[]}}`))
+	})
+	mux.HandleFunc("GET /ohpmweb/registry/oh-package/openapi/v1/publisher/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":200,"body":{"name":"Corp","packageCount":1}}`))
+	})
+	internal := httptest.NewServer(mux)
+	defer internal.Close()
+
+	client := NewClient("")
+	client.BaseURL = "http://127.0.0.1:1" // 默认 registry 不可达，确保请求未发往默认 registry
+	client.MaxAttempts = 1
+	client.Registries = []Registry{{Name: "internal", BaseURL: internal.URL, WebURL: "https://web.corp.example.com", Authorization: "Bearer internal"}}
+	ctx := context.Background()
+
+	names, err := client.PublisherPackages(ctx, "internal=corp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"internal=@corp/a"}) {
+		t.Errorf("names = %q", names)
+	}
+
+	got, err := client.FetchPackage(ctx, "internal=@corp/a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Code != 1 || got.Name != "@corp/a" || got.Registry != "internal" {
+		t.Errorf("got %+v", got)
+	}
+	if want := "https://web.corp.example.com/#/cn/detail/@corp%2Fa"; got.DetailURL() != want {
+		t.Errorf("DetailURL() = %q, want %q", got.DetailURL(), want)
+	}
+
+	profiles, err := client.PublisherProfiles(ctx, "internal=corp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(profiles) != 1 || profiles[0].Name != "Corp" || profiles[0].DetailURL() != "https://web.corp.example.com/#/cn/publisher/corp" {
+		t.Errorf("profiles = %+v", profiles)
+	}
+
+	if _, err := client.FetchPackage(ctx, "unknown=@corp/a"); err == nil {
+		t.Error("expected error for unknown registry")
+	}
+}
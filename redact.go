@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// 密钥在输出中的替换文本
+const redactedText = "***"
+
+// 隐藏输出中已配置的密钥（令牌、Authorization 请求头、webhook 地址）
+type redactor struct {
+	mutex    sync.RWMutex
+	secrets  []string
+	replacer *strings.Replacer
+}
+
+// 进程输出（os.Stdout / os.Stderr）使用的密钥，由 [newClient] 等在解析参数后添加
+var defaultRedactor = &redactor{}
+
+// 添加密钥
+//
+// 过短的值（如 "x"）会误伤正常输出，不视为密钥。
+//
+// 参数:
+//   - [secrets] 密钥（空值忽略）
+func (r *redactor) add(secrets ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, secret := range secrets {
+		secret = strings.TrimSpace(secret)
+		if len(secret) < 4 || slices.Contains(r.secrets, secret) {
+			continue
+		}
+		r.secrets = append(r.secrets, secret)
+	}
+	// 长的密钥优先替换，避免包含关系（如 "Bearer xxx" 与 "xxx"）只替换一部分
+	sort.Slice(r.secrets, func(i, j int) bool { return len(r.secrets[i]) > len(r.secrets[j]) })
+	pairs := []string{}
+	for _, secret := range r.secrets {
+		pairs = append(pairs, secret, redactedText)
+	}
+	r.replacer = strings.NewReplacer(pairs...)
+}
+
+// 将文本中的密钥替换为 [redactedText]
+//
+// 参数:
+//   - [value] 文本
+func (r *redactor) redact(value string) string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if r.replacer == nil {
+		return value
+	}
+	return r.replacer.Replace(value)
+}
+
+// 将 os.Stdout / os.Stderr 替换为管道，逐行隐藏密钥后写回原输出，
+// 覆盖 CLI 与 ohpm 库打印的每一行（包括包装了请求地址的错误）。
+//
+// 按行处理，避免密钥被拆分在两次写入之间而漏掉。
+//
+// 参数:
+//   - [r] 密钥
+//
+// 返回值:
+//   - 恢复函数：关闭管道、等待剩余输出写完并恢复 os.Stdout / os.Stderr
+func redactStdio(r *redactor) func() {
+	restores := []func(){}
+	for _, target := range []**os.File{&os.Stdout, &os.Stderr} {
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			continue // 无法创建管道时保持原输出
+		}
+		original := *target
+		*target = writer
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			copyRedacted(original, reader, r)
+		}()
+		restores = append(restores, func() {
+			writer.Close()
+			<-done
+			reader.Close()
+			*target = original
+		})
+	}
+	return func() {
+		for _, restore := range restores {
+			restore()
+		}
+	}
+}
+
+// 逐行复制并隐藏密钥
+//
+// 参数:
+//   - [w] 输出
+//   - [src] 输入（读到 EOF 为止）
+//   - [r] 密钥
+func copyRedacted(w io.Writer, src io.Reader, r *redactor) {
+	reader := bufio.NewReader(src)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			io.WriteString(w, r.redact(line))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// 客户端中配置的密钥：Github 令牌、registry 的 Authorization 请求头与地址中的密码
+//
+// 参数:
+//   - [client] 客户端
+func clientSecrets(client *ohpm.Client) []string {
+	secrets := []string{client.GithubToken}
+	for _, host := range client.GithubHosts {
+		secrets = append(secrets, host.Token)
+	}
+	registries := append([]ohpm.Registry{{BaseURL: client.BaseURL, WebURL: client.WebURL, Authorization: client.Authorization}}, client.Registries...)
+	for _, registry := range registries {
+		secrets = append(secrets, authorizationSecrets(registry.Authorization)...)
+		secrets = append(secrets, urlPassword(registry.BaseURL), urlPassword(registry.WebURL))
+	}
+	return secrets
+}
+
+// Authorization 请求头中的密钥：完整值，以及 "Bearer xxx" 等形式中的凭据部分
+//
+// 参数:
+//   - [authorization] Authorization 请求头
+func authorizationSecrets(authorization string) []string {
+	secrets := []string{authorization}
+	if _, credentials, ok := strings.Cut(authorization, " "); ok {
+		secrets = append(secrets, credentials)
+	}
+	return secrets
+}
+
+// 地址中 userinfo 的密码（无密码时为空）
+//
+// 参数:
+//   - [rawURL] 地址
+func urlPassword(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.User == nil {
+		return ""
+	}
+	password, _ := parsed.User.Password()
+	return password
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// registry 名称仅允许字母、数字、`_`、`-`，不能包含前缀分隔符 `=` 与列表分隔符 `,`
+var registryNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// 读取具名 registry 配置
+//
+// 除默认 registry（`-registryURL`）外，还可在同一仪表盘中混合其他具名 registry 的 package，
+// publisherList / packageList 中以 "名称=" 前缀指定 registry，如 "internal=@corp/utils"。
+// authorization 中的 `$VAR` / `${VAR}` 从环境变量展开，避免将令牌写入仓库。
+//
+// 配置文件:
+//
+//	[{"name": "internal", "baseURL": "https://ohpm.corp.example.com", "webURL": "https://ohpm-web.corp.example.com", "authorization": "Bearer ${OHPM_INTERNAL_TOKEN}"}]
+//
+// 参数:
+//   - [filename] 配置 JSON 文件（[ohpm.Registry] 数组），为空时无具名 registry
+//
+// 返回值:
+//   - registry 列表（authorization 已展开环境变量）
+func loadRegistries(filename string) ([]ohpm.Registry, error) {
+	if filename == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("🌏❌ loadRegistries: Error reade a file: %w", err)
+	}
+	registries := []ohpm.Registry{}
+	if err := json.Unmarshal(data, &registries); err != nil {
+		return nil, fmt.Errorf("🌏❌ loadRegistries: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for i := range registries {
+		registry := &registries[i]
+		if !registryNameRegexp.MatchString(registry.Name) {
+			return nil, fmt.Errorf("🌏❌ loadRegistries: invalid registry name %q", registry.Name)
+		}
+		if seen[registry.Name] {
+			return nil, fmt.Errorf("🌏❌ loadRegistries: duplicate registry name %q", registry.Name)
+		}
+		seen[registry.Name] = true
+		if registry.BaseURL == "" {
+			return nil, fmt.Errorf("🌏❌ loadRegistries: registry %q has no baseURL", registry.Name)
+		}
+		registry.BaseURL = strings.TrimSuffix(registry.BaseURL, "/")
+		registry.WebURL = strings.TrimSuffix(registry.WebURL, "/")
+		registry.Authorization = os.ExpandEnv(registry.Authorization)
+	}
+	return registries, nil
+}
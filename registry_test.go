@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadRegistries(t *testing.T) {
+	t.Run("empty filename", func(t *testing.T) {
+		registries, err := loadRegistries("")
+		if err != nil || registries != nil {
+			t.Errorf("got %v, %v", registries, err)
+		}
+	})
+
+	t.Run("expands authorization from environment", func(t *testing.T) {
+		t.Setenv("OHPM_INTERNAL_TOKEN", "secret")
+		filename := filepath.Join(t.TempDir(), "registries.json")
+		os.WriteFile(filename, []byte(`[{"name":"internal","baseURL":"https://ohpm.corp.example.com/","authorization":"Bearer ${OHPM_INTERNAL_TOKEN}"}]`), 0644)
+		registries, err := loadRegistries(filename)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(registries) != 1 || registries[0].BaseURL != "https://ohpm.corp.example.com" || registries[0].Authorization != "Bearer secret" {
+			t.Errorf("got %+v", registries)
+		}
+	})
+
+	for name, content := range map[string]string{
+		"invalid name":   `[{"name":"a=b","baseURL":"https://x"}]`,
+		"duplicate name": `[{"name":"a","baseURL":"https://x"},{"name":"a","baseURL":"https://y"}]`,
+		"missing url":    `[{"name":"a"}]`,
+		"invalid json":   `{`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "registries.json")
+			os.WriteFile(filename, []byte(content), 0644)
+			if _, err := loadRegistries(filename); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestNewClientRegistry(t *testing.T) {
+	client, err := newClient(clientOptions{registryURL: "https://ohpm.corp.example.com/", registryAuth: "Bearer x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.BaseURL != "https://ohpm.corp.example.com" || client.WebURL != client.BaseURL || client.Authorization != "Bearer x" {
+		t.Errorf("got %+v", client)
+	}
+
+	client, _ = newClient(clientOptions{registryURL: "https://api.corp.example.com", registryWebURL: "https://web.corp.example.com"})
+	if client.WebURL != "https://web.corp.example.com" {
+		t.Errorf("WebURL = %q", client.WebURL)
+	}
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/dashboard"
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// serve 子命令：以 HTTP 服务提供实时仪表盘，后台按 [interval] 定时刷新数据
+//
+// 使用:
+//   - `go run . serve -addr :8080 -interval 1h -githubToken xxx -publisherList xxx -packageList xxx`
+//   - `go run . serve -addr :8080 -config dashboards.json`
+//
+// 接口:
+//   - `GET /healthz`                   健康检查
+//   - `GET /dashboards`                仪表盘名称列表（JSON）
+//   - `GET /dashboards/{name}.md`      仪表盘表格（Markdown）
+//   - `GET /dashboards/{name}.html`    仪表盘表格（HTML）
+//   - `GET /dashboards/{name}.json`    [ohpm.PackageInfo] 列表（JSON）
+//   - `GET /dashboards/{name}.atom`    最近发布版本（Atom feed），见 feed.go
+//   - `GET /badges/{metric}/{package}` shields.io endpoint 徽章（JSON），见 badge.go
+//   - `GET /metrics`                   Prometheus 指标，见 metrics.go
+//
+// 参数:
+//   - [addr]        监听地址，默认 ":8080"
+//   - [interval]    刷新间隔，默认 "1h"
+//   - [githubToken] 拥有 repo 权限的 Github 令牌
+//   - [config]      仪表盘配置 JSON 文件（[dashboard.Config] 数组），为空时使用命令行参数作为 "default" 仪表盘
+//   - 其余参数同 update 流程：publisherList、packageList、searchQuery、searchMax、sortField、sortMode
+//
+// 返回值:
+//   - 进程退出码
+func runServe(args []string) int {
+	var addr, configFile string
+	var interval time.Duration
+	var options clientOptions
+	var config dashboard.Config
+	flagSet := newCommandFlagSet("serve", serveSummary)
+	registerFetchFlags(flagSet, &options, &config)
+	flagSet.StringVar(&addr, "addr", ":8080", "监听地址")
+	flagSet.DurationVar(&interval, "interval", time.Hour, "刷新间隔 如: 30m, 1h")
+	flagSet.StringVar(&configFile, "config", "", "仪表盘配置 JSON 文件（为空时使用命令行参数）")
+	if code, ok := parseCommandFlags(flagSet, args); !ok {
+		return code
+	}
+
+	configs, err := loadDashboardConfigs(configFile, config)
+	if err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	if interval <= 0 {
+		slog.Error("🌐❌ serve: interval must be positive")
+		return exitError
+	}
+
+	client, err := newClient(options)
+	if err != nil {
+		slog.Error(err.Error())
+		return exitError
+	}
+	server := newDashboardServer(configs, interval, func(ctx context.Context, config dashboard.Config) ([]ohpm.PackageInfo, error) {
+		return dashboard.Fetch(ctx, client, config)
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go server.run(ctx)
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           server.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("🌐 serve: listening", "addr", addr, "interval", interval)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("🌐❌ serve", "error", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// 仪表盘名称仅允许字母、数字、`_`、`-`，以便直接用于 URL 路径
+var dashboardNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// 读取仪表盘配置
+//
+// 参数:
+//   - [configFile] 配置 JSON 文件（[dashboard.Config] 数组），为空时使用 [fallback]
+//   - [fallback]   命令行参数中的仪表盘配置
+//
+// 返回值:
+//   - 补全默认值后的仪表盘配置列表
+func loadDashboardConfigs(configFile string, fallback dashboard.Config) ([]dashboard.Config, error) {
+	configs := []dashboard.Config{fallback}
+	if configFile != "" {
+		data, err := os.ReadFile(configFile)
+		if err != nil {
+			return nil, fmt.Errorf("🌐❌ loadDashboardConfigs: Error reade a file: %w", err)
+		}
+		configs = nil
+		if err := json.Unmarshal(data, &configs); err != nil {
+			return nil, fmt.Errorf("🌐❌ loadDashboardConfigs: %w", err)
+		}
+	}
+	if len(configs) == 0 {
+		return nil, errors.New("🌐❌ loadDashboardConfigs: no dashboard configured")
+	}
+
+	seen := map[string]bool{}
+	for i := range configs {
+		config := &configs[i]
+		if config.Name == "" {
+			config.Name = "default"
+		}
+		if config.SearchMax == 0 {
+			config.SearchMax = 100
+		}
+		if config.SortField == "" {
+			config.SortField = "name"
+		}
+		if config.SortMode == "" {
+			config.SortMode = "asc"
+		}
+		if config.HealthWeights == "" {
+			config.HealthWeights = dashboard.DefaultHealthWeights
+		}
+		if _, err := dashboard.ParseHealthWeights(config.HealthWeights); err != nil {
+			return nil, err
+		}
+		if !dashboardNameRegexp.MatchString(config.Name) {
+			return nil, fmt.Errorf("🌐❌ loadDashboardConfigs: invalid dashboard name %q", config.Name)
+		}
+		if seen[config.Name] {
+			return nil, fmt.Errorf("🌐❌ loadDashboardConfigs: duplicate dashboard name %q", config.Name)
+		}
+		seen[config.Name] = true
+	}
+	return configs, nil
+}
+
+// 单个仪表盘的最新渲染结果
+type renderedDashboard struct {
+	UpdatedAt       time.Time
+	PackageInfoList []ohpm.PackageInfo
+	Markdown        []byte
+	HTML            []byte
+	JSON            []byte
+	Atom            []byte
+}
+
+// dashboardServer 持有各仪表盘的最新渲染结果，后台定时刷新，HTTP 请求只读缓存。
+type dashboardServer struct {
+	configs  []dashboard.Config
+	interval time.Duration
+	// 抓取单个仪表盘数据，可在测试中替换
+	fetch func(ctx context.Context, config dashboard.Config) ([]ohpm.PackageInfo, error)
+
+	mutex      sync.RWMutex
+	dashboards map[string]renderedDashboard
+}
+
+func newDashboardServer(configs []dashboard.Config, interval time.Duration, fetch func(ctx context.Context, config dashboard.Config) ([]ohpm.PackageInfo, error)) *dashboardServer {
+	return &dashboardServer{
+		configs:    configs,
+		interval:   interval,
+		fetch:      fetch,
+		dashboards: map[string]renderedDashboard{},
+	}
+}
+
+// 立即刷新一次，之后每隔 [interval] 刷新，直至 ctx 取消
+func (s *dashboardServer) run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// 依次刷新所有仪表盘；单个仪表盘失败时保留上一次的结果
+func (s *dashboardServer) refresh(ctx context.Context) {
+	for _, config := range s.configs {
+		packageInfoList, err := s.fetch(ctx, config)
+		if err != nil {
+			slog.Warn("🌐⚠️ serve: refresh", "dashboard", config.Name, "error", err)
+			continue
+		}
+		if err := s.update(config, packageInfoList, time.Now()); err != nil {
+			slog.Warn("🌐⚠️ serve: render", "dashboard", config.Name, "error", err)
+			continue
+		}
+		slog.Info("🌐✅ serve: refresh", "dashboard", config.Name, "total", len(packageInfoList))
+	}
+}
+
+// 渲染并缓存仪表盘
+//
+// 参数:
+//   - [config]          仪表盘配置
+//   - [packageInfoList] 已排序的信息列表
+//   - [updatedAt]       数据更新时间
+func (s *dashboardServer) update(config dashboard.Config, packageInfoList []ohpm.PackageInfo, updatedAt time.Time) error {
+	markdown := dashboard.AssembleMarkdownTable(packageInfoList, config.SortField)
+	jsonData, err := json.MarshalIndent(packageInfoList, "", "  ")
+	if err != nil {
+		return err
+	}
+	atomData, err := dashboard.AssembleAtomFeed("ohpm-dashboard releases: "+config.Name, packageInfoList, updatedAt)
+	if err != nil {
+		return err
+	}
+	rendered := renderedDashboard{
+		// Last-Modified 仅精确到秒
+		UpdatedAt:       updatedAt.UTC().Truncate(time.Second),
+		PackageInfoList: packageInfoList,
+		Markdown:        []byte(markdown),
+		HTML:            []byte(dashboard.AssembleHTMLPage(config.Name, markdown, updatedAt)),
+		JSON:            jsonData,
+		Atom:            atomData,
+	}
+	s.mutex.Lock()
+	s.dashboards[config.Name] = rendered
+	s.mutex.Unlock()
+	return nil
+}
+
+func (s *dashboardServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("GET /dashboards", s.handleDashboardList)
+	mux.HandleFunc("GET /dashboards/{file}", s.handleDashboard)
+	mux.HandleFunc("GET /badges/{metric}/{name...}", s.handleBadge)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+	return mux
+}
+
+func (s *dashboardServer) handleDashboardList(w http.ResponseWriter, r *http.Request) {
+	names := make([]string, 0, len(s.configs))
+	for _, config := range s.configs {
+		names = append(names, config.Name)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	json.NewEncoder(w).Encode(names)
+}
+
+func (s *dashboardServer) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	ext := path.Ext(file)
+	name := strings.TrimSuffix(file, ext)
+
+	known := false
+	for _, config := range s.configs {
+		if config.Name == name {
+			known = true
+			break
+		}
+	}
+	contentTypes := map[string]string{
+		".md":   "text/markdown; charset=utf-8",
+		".html": "text/html; charset=utf-8",
+		".json": "application/json; charset=utf-8",
+		".atom": "application/atom+xml; charset=utf-8",
+	}
+	contentType, ok := contentTypes[ext]
+	if !known || !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mutex.RLock()
+	rendered, loaded := s.dashboards[name]
+	s.mutex.RUnlock()
+	if !loaded {
+		// 首次刷新尚未完成
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, "dashboard is not ready yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	var content []byte
+	switch ext {
+	case ".md":
+		content = rendered.Markdown
+	case ".html":
+		content = rendered.HTML
+	case ".json":
+		content = rendered.JSON
+	case ".atom":
+		content = rendered.Atom
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(s.interval.Seconds())))
+	// ServeContent 处理 Last-Modified / If-Modified-Since
+	http.ServeContent(w, r, file, rendered.UpdatedAt, bytes.NewReader(content))
+}
+
+// 按 package 名称在所有仪表盘中查找，返回 shields.io endpoint 徽章
+func (s *dashboardServer) handleBadge(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	var packageInfo ohpm.PackageInfo
+	var updatedAt time.Time
+	found := false
+	s.mutex.RLock()
+	for _, config := range s.configs {
+		for _, value := range s.dashboards[config.Name].PackageInfoList {
+			if value.Name == name {
+				packageInfo, updatedAt, found = value, s.dashboards[config.Name].UpdatedAt, true
+				break
+			}
+		}
+		if found {
+			break
+		}
+	}
+	s.mutex.RUnlock()
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	endpoint, ok := dashboard.AssembleShieldsEndpoint(packageInfo, r.PathValue("metric"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	content, err := json.Marshal(endpoint)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(s.interval.Seconds())))
+	http.ServeContent(w, r, "", updatedAt, bytes.NewReader(content))
+}
+
+// 输出所有仪表盘 package 的 Prometheus 指标与 HTTP 指标
+func (s *dashboardServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	packageInfoList := []ohpm.PackageInfo{}
+	refreshTimestamps := ""
+	s.mutex.RLock()
+	for _, config := range s.configs {
+		rendered, ok := s.dashboards[config.Name]
+		if !ok {
+			continue
+		}
+		packageInfoList = append(packageInfoList, rendered.PackageInfoList...)
+		refreshTimestamps += `ohpm_dashboard_last_refresh_timestamp_seconds{dashboard="` + escapeLabelValue(config.Name) + `"} ` + strconv.FormatInt(rendered.UpdatedAt.Unix(), 10) + "\n"
+	}
+	s.mutex.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write([]byte(assemblePrometheusMetrics(packageInfoList, defaultHTTPMetrics.snapshot())))
+	w.Write([]byte("# HELP ohpm_dashboard_last_refresh_timestamp_seconds Unix time of the last successful dashboard refresh.\n" +
+		"# TYPE ohpm_dashboard_last_refresh_timestamp_seconds gauge\n" +
+		refreshTimestamps))
+}
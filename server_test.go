@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/dashboard"
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+func TestLoadDashboardConfigs(t *testing.T) {
+	t.Run("falls back to command line config", func(t *testing.T) {
+		got, err := loadDashboardConfigs("", dashboard.Config{PackageList: "a"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []dashboard.Config{{Name: "default", PackageList: "a", SearchMax: 100, SortField: "name", SortMode: "asc", HealthWeights: dashboard.DefaultHealthWeights}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("reads config file", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "dashboards.json")
+		os.WriteFile(configFile, []byte(`[{"name":"candies","publisherList":"p1","sortField":"ohpmDownloads","sortMode":"desc"},{"name":"charts","searchQuery":"keyword:chart"}]`), 0644)
+		got, err := loadDashboardConfigs(configFile, dashboard.Config{PackageList: "ignored"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 2 || got[0].Name != "candies" || got[0].SortMode != "desc" || got[1].SortField != "name" || got[1].PackageList != "" {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("rejects invalid names and health weights", func(t *testing.T) {
+		for _, content := range []string{
+			`[{"name":"a/b"}]`,
+			`[{"name":"a","healthWeights":"stars=10"}]`,
+			`[{"name":"a"},{"name":"a"}]`,
+			`[]`,
+		} {
+			configFile := filepath.Join(t.TempDir(), "dashboards.json")
+			os.WriteFile(configFile, []byte(content), 0644)
+			if _, err := loadDashboardConfigs(configFile, dashboard.Config{}); err == nil {
+				t.Errorf("%s: expected error", content)
+			}
+		}
+	})
+}
+
+func TestDashboardServer(t *testing.T) {
+	configs := []dashboard.Config{{Name: "candies", SortField: "name"}, {Name: "pending", SortField: "name"}}
+	server := newDashboardServer(configs, time.Hour, nil)
+	updatedAt := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	list := []ohpm.PackageInfo{{Code: 1, Name: "@candies/a", Version: "1.0.0"}, {Code: 0, Name: "missing"}}
+	if err := server.update(configs[0], list, updatedAt); err != nil {
+		t.Fatal(err)
+	}
+	handler := server.handler()
+
+	get := func(target string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("healthz", func(t *testing.T) {
+		rec := get("/healthz", nil)
+		if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+			t.Errorf("got %d %q", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("dashboard list", func(t *testing.T) {
+		var names []string
+		rec := get("/dashboards", nil)
+		if err := json.Unmarshal(rec.Body.Bytes(), &names); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(names, []string{"candies", "pending"}) {
+			t.Errorf("got %v", names)
+		}
+	})
+
+	t.Run("markdown with cache headers", func(t *testing.T) {
+		rec := get("/dashboards/candies.md", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d", rec.Code)
+		}
+		if got := rec.Header().Get("Content-Type"); got != "text/markdown; charset=utf-8" {
+			t.Errorf("Content-Type = %q", got)
+		}
+		if got := rec.Header().Get("Cache-Control"); got != "public, max-age=3600" {
+			t.Errorf("Cache-Control = %q", got)
+		}
+		if got := rec.Header().Get("Last-Modified"); got != updatedAt.Format(http.TimeFormat) {
+			t.Errorf("Last-Modified = %q", got)
+		}
+		if !strings.Contains(rec.Body.String(), "[@candies/a](https://ohpm.openharmony.cn/#/cn/detail/@candies%2Fa)") {
+			t.Errorf("unexpected body %q", rec.Body.String())
+		}
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		rec := get("/dashboards/candies.md", http.Header{"If-Modified-Since": {updatedAt.Format(http.TimeFormat)}})
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("status = %d, want 304", rec.Code)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		rec := get("/dashboards/candies.html", nil)
+		body := rec.Body.String()
+		if rec.Code != http.StatusOK || !strings.HasPrefix(body, "<!DOCTYPE html>") {
+			t.Fatalf("got %d %q", rec.Code, body)
+		}
+		if !strings.Contains(body, `<a href="https://ohpm.openharmony.cn/#/cn/detail/@candies%2Fa">@candies/a</a>`) {
+			t.Errorf("link not converted in %q", body)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var got []ohpm.PackageInfo
+		rec := get("/dashboards/candies.json", nil)
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 || got[0].Name != "@candies/a" {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("atom", func(t *testing.T) {
+		rec := get("/dashboards/candies.atom", nil)
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/atom+xml; charset=utf-8" {
+			t.Fatalf("got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+		}
+		if !strings.Contains(rec.Body.String(), "<title>@candies/a v1.0.0</title>") {
+			t.Errorf("unexpected body %q", rec.Body.String())
+		}
+	})
+
+	t.Run("badge", func(t *testing.T) {
+		var got dashboard.ShieldsEndpoint
+		rec := get("/badges/version/@candies/a", nil)
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("status %d: %v", rec.Code, err)
+		}
+		if got.Message != "v1.0.0" || got.SchemaVersion != 1 {
+			t.Errorf("got %+v", got)
+		}
+		for _, target := range []string{"/badges/version/@candies/unknown", "/badges/stars/@candies/a"} {
+			if rec := get(target, nil); rec.Code != http.StatusNotFound {
+				t.Errorf("%s: status = %d, want 404", target, rec.Code)
+			}
+		}
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		rec := get("/metrics", nil)
+		body := rec.Body.String()
+		if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+			t.Fatalf("got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+		}
+		for _, want := range []string{
+			`ohpm_package_found{package="@candies/a",publisher=""} 1`,
+			`ohpm_dashboard_last_refresh_timestamp_seconds{dashboard="candies"} ` + strconv.FormatInt(updatedAt.Unix(), 10),
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("missing %q in %q", want, body)
+			}
+		}
+	})
+
+	t.Run("not ready yet", func(t *testing.T) {
+		rec := get("/dashboards/pending.md", nil)
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("status = %d, want 503", rec.Code)
+		}
+	})
+
+	t.Run("unknown dashboard or format", func(t *testing.T) {
+		for _, target := range []string{"/dashboards/unknown.md", "/dashboards/candies.txt", "/dashboards/candies"} {
+			if rec := get(target, nil); rec.Code != http.StatusNotFound {
+				t.Errorf("%s: status = %d, want 404", target, rec.Code)
+			}
+		}
+	})
+}
+
+func TestDashboardServerRefresh(t *testing.T) {
+	configs := []dashboard.Config{{Name: "ok", SortField: "name"}, {Name: "broken", SortField: "name"}}
+	server := newDashboardServer(configs, time.Hour, func(ctx context.Context, config dashboard.Config) ([]ohpm.PackageInfo, error) {
+		if config.Name == "broken" {
+			return nil, errors.New("boom")
+		}
+		return []ohpm.PackageInfo{{Code: 1, Name: "a"}}, nil
+	})
+	server.refresh(context.Background())
+
+	if _, ok := server.dashboards["ok"]; !ok {
+		t.Error("dashboard ok was not refreshed")
+	}
+	if _, ok := server.dashboards["broken"]; ok {
+		t.Error("failed dashboard must not be cached")
+	}
+}
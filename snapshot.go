@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/AmosHuKe/ohpm-dashboard/ohpm"
+)
+
+// 快照文件格式版本，字段有不兼容变更时递增
+const snapshotVersion = 1
+
+// 运行快照：一次运行抓取到的全部数据
+//
+// 用于:
+//   - `-stateFile` 与上一次运行对比得出通知事件，见 notify.go
+//   - `fetch` / `-saveSnapshot` 保存，`render` / `-fromSnapshot` 离线渲染
+type Snapshot struct {
+	Version     int                     `json:"version"`
+	GeneratedAt time.Time               `json:"generatedAt"`
+	Packages    []ohpm.PackageInfo      `json:"packages"`
+	Publishers  []ohpm.PublisherProfile `json:"publishers,omitempty"`
+}
+
+// 读取快照文件
+//
+// 参数:
+//   - [filename] 快照文件
+//
+// 返回值:
+//   - [Snapshot] 内容
+//   - 文件是否存在（首次运行时不存在，非错误）
+func readSnapshot(filename string) (Snapshot, bool, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return Snapshot{}, false, nil
+	}
+	if err != nil {
+		return Snapshot{}, false, fmt.Errorf("💾❌ readSnapshot: Error reade a file: %w", err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, false, fmt.Errorf("💾❌ readSnapshot: %w", err)
+	}
+	if snapshot.Version > snapshotVersion {
+		return Snapshot{}, false, fmt.Errorf("💾❌ readSnapshot: %s has version %d, newer than supported version %d", filename, snapshot.Version, snapshotVersion)
+	}
+	return snapshot, true, nil
+}
+
+// 写入快照文件
+//
+// 参数:
+//   - [filename] 快照文件
+//   - [snapshot] 内容（Version 为空时写入当前版本，GeneratedAt 转为 UTC）
+func writeSnapshot(filename string, snapshot Snapshot) error {
+	if snapshot.Version == 0 {
+		snapshot.Version = snapshotVersion
+	}
+	snapshot.GeneratedAt = snapshot.GeneratedAt.UTC()
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("💾❌ writeSnapshot: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("💾❌ writeSnapshot: Error writing a file: %w", err)
+	}
+	slog.Info("💾✅ writeSnapshot: Success", "file", filename)
+	return nil
+}